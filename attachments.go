@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+)
+
+const (
+	// Title Lavalink's HTTP source uses for files without metadata
+	UNKNOWN_TITLE = "Unknown title"
+)
+
+var (
+	AUDIO_EXTENSIONS = []string{".mp3", ".ogg", ".flac", ".wav", ".m4a", ".opus"}
+
+	// Attachment links expire after about a day, so these tracks are not saved to playlists or exported
+	DISCORD_CDN_URLS = []string{"https://cdn.discordapp.com/", "https://media.discordapp.net/"}
+)
+
+func isAudio(attachment discord.Attachment) bool {
+	if attachment.ContentType != nil && strings.HasPrefix(*attachment.ContentType, "audio/") {
+		return true
+	}
+
+	return slices.Contains(AUDIO_EXTENSIONS, strings.ToLower(path.Ext(attachment.Filename)))
+}
+
+// Loads the file from the Discord CDN through Lavalink's HTTP source.
+func playAttachment(event *events.ApplicationCommandInteractionCreate, attachment discord.Attachment) {
+	if !isAudio(attachment) {
+		replyEphemeral(event, fmt.Sprintf("%s is not an audio file.", attachment.Filename))
+		return
+	}
+
+	user := newUserInfo(event)
+
+	track, err := handleUserQuery(user, attachment.URL)
+	if err != nil {
+		reply(event, err.Error())
+		return
+	}
+
+	// Kept in the user data so Lavalink's events and the saved state also show it
+	if track.Info.Title == "" || track.Info.Title == UNKNOWN_TITLE {
+		user.Title = attachment.Filename
+
+		track, err = track.WithUserData(user)
+		if err != nil {
+			eventLogger(event).Error("Error adding user data", "err", err)
+			reply(event, err.Error())
+			return
+		}
+	}
+
+	enqueue(event, track)
+}
+
+func isAttachmentTrack(track lavalink.Track) bool {
+	if track.Info.URI == nil {
+		return false
+	}
+
+	for _, url := range DISCORD_CDN_URLS {
+		if strings.HasPrefix(*track.Info.URI, url) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func playMessageCommand(event *events.ApplicationCommandInteractionCreate) {
+	playMessage(event, event.MessageCommandInteractionData().TargetMessage())
+}
+
+// Plays the first audio file attached to the message, used by the "Play in voice" context menu.
+func playMessage(event *events.ApplicationCommandInteractionCreate, message discord.Message) {
+	for _, attachment := range message.Attachments {
+		if isAudio(attachment) {
+			playAttachment(event, attachment)
+			return
+		}
+	}
+
+	replyEphemeral(event, "That message has no audio files.")
+}
+
+var (
+	ATTACHMENT_COMMANDS = []Command{
+		{
+			Create: discord.MessageCommandCreate{
+				Name: "Play in voice",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Play in voice",
+					discord.LocalePortugueseBR: "Tocar no canal de voz",
+				},
+			},
+			Handler:   playMessageCommand,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+	}
+)
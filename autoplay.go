@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/disgolink"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	AUTOPLAY_USERNAME = "Autoplay"
+)
+
+// Loads a track related to the given one that wasn't played recently.
+// YouTube tracks use the video's mix, others search for the same author.
+func relatedTrack(guildID snowflake.ID, seed lavalink.Track) (lavalink.Track, error) {
+	query := fmt.Sprintf("ytsearch:%s %s", seed.Info.Author, trackTitle(seed))
+	if seed.Info.SourceName == "youtube" {
+		query = fmt.Sprintf("https://www.youtube.com/watch?v=%s&list=RD%s", seed.Info.Identifier, seed.Info.Identifier)
+	}
+
+	var candidates []lavalink.Track
+	var err error
+
+	loadingNode().LoadTracksHandler(context.TODO(), query, disgolink.NewResultHandler(
+		func(track lavalink.Track) {
+			candidates = []lavalink.Track{track}
+		},
+		func(playlist lavalink.Playlist) {
+			candidates = playlist.Tracks
+		},
+		func(tracks []lavalink.Track) {
+			candidates = tracks
+		},
+		func() {
+			err = ErrNoTracksFound
+		},
+		func(lavalinkErr error) {
+			trackLogger(guildID, seed).Error("Error loading related tracks", "err", lavalinkErr)
+			err = ErrLoadingTracks
+		},
+	))
+
+	if err != nil {
+		return lavalink.Track{}, err
+	}
+
+	// Skips anything in the history, by identifier or title since the same song is often uploaded more than once
+	played := map[string]bool{
+		seed.Info.Identifier:              true,
+		strings.ToLower(trackTitle(seed)): true,
+	}
+
+	for _, entry := range GUILDS.Get(guildID).History {
+		played[entry.Track.Info.Identifier] = true
+		played[strings.ToLower(trackTitle(entry.Track))] = true
+	}
+
+	seedUser := UserInfo{}
+	err = seed.UserData.Unmarshal(&seedUser)
+	if err != nil {
+		guildLogger(guildID).Error("Error scanning user", "err", err)
+	}
+
+	user := UserInfo{
+		Username:  AUTOPLAY_USERNAME,
+		ChannelID: seedUser.ChannelID,
+	}
+
+	for _, track := range candidates {
+		if track.Info.IsStream || played[track.Info.Identifier] || played[strings.ToLower(trackTitle(track))] {
+			continue
+		}
+
+		return track.WithUserData(user)
+	}
+
+	return lavalink.Track{}, ErrNoTracksFound
+}
+
+// Queues and plays a track related to the one that just ended, returns false if none was found.
+func autoplay(player disgolink.Player, seed lavalink.Track) bool {
+	track, err := relatedTrack(player.GuildID(), seed)
+	if err != nil {
+		guildLogger(player.GuildID()).Error("Error finding related track", "err", err)
+		return false
+	}
+
+	trackLogger(player.GuildID(), track).Info("Autoplaying")
+	TRACKS.Push(track)
+
+	_, filters := guildFilters(player.GuildID())
+
+	err = player.Update(context.TODO(), lavalink.WithTrack(track), lavalink.WithFilters(filters))
+	if err != nil {
+		guildLogger(player.GuildID()).Error("Error autoplaying track", "err", err)
+		TRACKS.Clear()
+		return false
+	}
+
+	return true
+}
+
+func toggleAutoplay(event *events.ApplicationCommandInteractionCreate) {
+	guildID := *event.GuildID()
+
+	if !requireSameChannel(event) {
+		return
+	}
+
+	enabled := !SETTINGS.Get(guildID).Autoplay
+
+	err := SETTINGS.Update(guildID, func(settings *GuildSettings) {
+		settings.Autoplay = enabled
+	})
+	if err != nil {
+		eventLogger(event).Error("Error saving settings", "err", err)
+		reply(event, err.Error())
+		return
+	}
+
+	if enabled {
+		reply(event, "Autoplay enabled, related songs will play when the queue runs out.")
+		return
+	}
+
+	reply(event, "Autoplay disabled.")
+}
+
+var (
+	AUTOPLAY_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name:        "autoplay",
+				Description: "Toggles playing related tracks when the queue runs out",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Toggles playing related tracks when the queue runs out",
+					discord.LocalePortugueseBR: "Alterna tocar músicas relacionadas quando a fila acabar",
+				},
+			},
+			Handler:   toggleAutoplay,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+	}
+)
@@ -3,11 +3,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 
 	"github.com/disgoorg/disgo"
 	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/cache"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/gateway"
 	"github.com/disgoorg/disgo/rest"
@@ -21,28 +23,14 @@ type Client struct {
 }
 
 func NewClient() error {
-	assets, err := os.ReadDir("./assets")
+	choices, err := loadAssets()
 	if err != nil {
 		return err
 	}
 
-	choices := make([]discord.ApplicationCommandOptionChoiceString, 0, 25)
-
-	for _, asset := range assets {
-		name := asset.Name()
-		name = name[:len(name)-len(filepath.Ext(name))]
-		ASSETS_PATHS[name] = fmt.Sprintf("./assets/%s", asset.Name())
-		ASSETS = append(ASSETS, name)
-
-		choices = append(choices, discord.ApplicationCommandOptionChoiceString{
-			Name:  name,
-			Value: name,
-		})
-	}
-
-	for i, command := range COMMANDS {
-		if command.CommandName() == "joel" {
-			COMMANDS[i].(discord.SlashCommandCreate).Options[0] = discord.ApplicationCommandOptionString{
+	for _, command := range COMMANDS {
+		if command.Name() == "joel" {
+			command.Create.(discord.SlashCommandCreate).Options[0] = discord.ApplicationCommandOptionString{
 				Name:        "joel",
 				Description: "JOEL",
 				DescriptionLocalizations: map[discord.Locale]string{
@@ -56,22 +44,42 @@ func NewClient() error {
 		}
 	}
 
-	bot, err := disgo.New(CONFIG.Token,
+	filterChoices := make([]discord.ApplicationCommandOptionChoiceString, 0, len(FILTERS))
+	for _, name := range FILTERS {
+		filterChoices = append(filterChoices, discord.ApplicationCommandOptionChoiceString{
+			Name:  name,
+			Value: name,
+		})
+	}
+
+	for _, command := range COMMANDS {
+		if command.Name() == "filter" {
+			options := command.Create.(discord.SlashCommandCreate).Options
+			option := options[0].(discord.ApplicationCommandOptionString)
+			option.Choices = filterChoices
+			options[0] = option
+			break
+		}
+	}
+
+	bot, err := disgo.New(CONFIG.Load().Discord.Token,
+		bot.WithLogger(slog.Default()),
+
 		bot.WithGatewayConfigOpts(
-			gateway.WithIntents(
-				gateway.IntentGuilds,
-				gateway.IntentGuildMessages,
-				gateway.IntentDirectMessages,
-				gateway.IntentMessageContent,
-				gateway.IntentGuildVoiceStates,
-			),
+			gateway.WithIntents(CONFIG.Load().GatewayIntents()),
 
 			gateway.WithPresenceOpts(
-				gateway.WithPlayingActivity("/help"),
-				gateway.WithOnlineStatus(discord.OnlineStatusDND),
+				gateway.WithPlayingActivity(CONFIG.Load().Discord.Presence.Text),
+				gateway.WithOnlineStatus(ONLINE_STATUSES[CONFIG.Load().Discord.Presence.Status]),
 			),
 		),
 
+		// Added to the default flags. Voice states and members are used to know who is listening with the bot,
+		// guilds, channels and roles let the cache work out member permissions
+		bot.WithCacheConfigOpts(
+			cache.WithCaches(cache.FlagGuilds, cache.FlagChannels, cache.FlagRoles, cache.FlagMembers, cache.FlagVoiceStates),
+		),
+
 		bot.WithEventListenerFunc(onReady),
 		bot.WithEventListenerFunc(onMessageCreate),
 
@@ -79,13 +87,15 @@ func NewClient() error {
 		bot.WithEventListenerFunc(onVoiceServerUpdate),
 
 		bot.WithEventListenerFunc(commandListener),
+		bot.WithEventListenerFunc(componentListener),
+		bot.WithEventListenerFunc(autocompleteListener),
 	)
 
 	if err != nil {
 		return err
 	}
 
-	fmt.Println("Connecting to Discord")
+	slog.Info("Connecting to Discord")
 	err = bot.OpenGateway(context.TODO())
 	if err != nil {
 		return err
@@ -98,198 +108,91 @@ func NewClient() error {
 	BOT_ID := bot.ID()
 	MENTION = fmt.Sprintf("<@%s>", APPLICATION_ID)
 
-	if *REGISTER {
-		cmds, err := bot.Rest().SetGlobalCommands(APPLICATION_ID, COMMANDS)
+	if *REGISTER && CONFIG.Load().Discord.DevGuild != 0 {
+		cmds, err := bot.Rest().SetGuildCommands(APPLICATION_ID, CONFIG.Load().Discord.DevGuild, enabledCommands())
 		if err != nil {
-			fmt.Printf("Error setting global commands: %v\n", err)
+			slog.Error("Error setting guild commands", "err", err)
 			panic(err)
 		}
 
-		fmt.Printf("Registered %d global commands\n", len(cmds))
+		slog.Info("Registered guild commands", "commands", len(cmds), "guild_id", CONFIG.Load().Discord.DevGuild)
+	} else if *REGISTER {
+		cmds, err := bot.Rest().SetGlobalCommands(APPLICATION_ID, enabledCommands())
+		if err != nil {
+			slog.Error("Error setting global commands", "err", err)
+			panic(err)
+		}
+
+		slog.Info("Registered global commands", "commands", len(cmds))
 	}
 
 	MENTION = fmt.Sprintf("<@%s>", BOT_ID)
 
-	fmt.Println("Connecting to Lavalink")
-	lavalink := disgolink.New(BOT_ID, disgolink.WithListenerFunc(onTrackEnd))
-	_, err = lavalink.AddNode(context.TODO(), disgolink.NodeConfig{
-		Name:    "joel",
-		Address: "0.0.0.0:2333",
-	})
+	CLIENT = Client{
+		Bot:  bot,
+		Rest: bot.Rest(),
+	}
+
+	if !CONFIG.Load().Features.Music {
+		return nil
+	}
+
+	lavalink := disgolink.New(BOT_ID,
+		disgolink.WithLogger(slog.Default()),
 
+		disgolink.WithListenerFunc(onTrackStart),
+		disgolink.WithListenerFunc(onTrackEnd),
+		disgolink.WithListenerFunc(onPlayerPause),
+		disgolink.WithListenerFunc(onPlayerResume),
+		disgolink.WithListenerFunc(onTrackException),
+		disgolink.WithListenerFunc(onTrackStuck),
+		disgolink.WithListenerFunc(onWebSocketClosed),
+	)
+	err = connectNodes(lavalink)
 	if err != nil {
 		return err
 	}
 
-	CLIENT = Client{
-		Bot:      bot,
-		Lavalink: lavalink,
-		Rest:     bot.Rest(),
-	}
+	CLIENT.Lavalink = lavalink
 
 	return nil
 }
 
-var (
-	COMMANDS = []discord.ApplicationCommandCreate{
-		discord.SlashCommandCreate{
-			Name: "help",
-			NameLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "help",
-				discord.LocalePortugueseBR: "ajuda",
-			},
-			Description: "Displays the help message",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Displays the help message",
-				discord.LocalePortugueseBR: "Exibe a mensagem de ajuda",
-			},
-		},
-
-		discord.SlashCommandCreate{
-			Name:        "joel",
-			Description: "JOEL",
-			Options: []discord.ApplicationCommandOption{
-				discord.ApplicationCommandOptionString{
-					Name:                     "",
-					Description:              "",
-					DescriptionLocalizations: map[discord.Locale]string{},
-					Choices:                  []discord.ApplicationCommandOptionChoiceString{},
-					Required:                 false,
-				},
-			},
-		},
-		discord.SlashCommandCreate{
-			Name:        "ttj",
-			Description: "Latency test",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Latency test",
-				discord.LocalePortugueseBR: "Teste de latência",
-			},
-		},
-
-		discord.SlashCommandCreate{
-			Name:        "play",
-			Description: "Plays a track",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Plays a track",
-				discord.LocalePortugueseBR: "Toca uma música",
-			},
-			Options: []discord.ApplicationCommandOption{
-				discord.ApplicationCommandOptionString{
-					Name: "query",
-					NameLocalizations: map[discord.Locale]string{
-						discord.LocaleEnglishUS:    "query",
-						discord.LocalePortugueseBR: "pesquisa",
-					},
-					Description: "Can be an URL or a search query",
-					DescriptionLocalizations: map[discord.Locale]string{
-						discord.LocaleEnglishUS:    "Can be an URL or a search query",
-						discord.LocalePortugueseBR: "Pode ser uma URL ou uma pesquisa",
-					},
-					Required: true,
-				},
-			},
-		},
-		discord.SlashCommandCreate{
-			Name: "stop",
-			NameLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "stop",
-				discord.LocalePortugueseBR: "parar",
-			},
-			Description: "Stops the current track",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Stops the current track",
-				discord.LocalePortugueseBR: "Para a música atual",
-			},
-		},
-		discord.SlashCommandCreate{
-			Name: "pause",
-			NameLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "pause",
-				discord.LocalePortugueseBR: "pausar",
-			},
-			Description: "Pauses the current track",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Pauses the current track",
-				discord.LocalePortugueseBR: "Pausa a música atual",
-			},
-		},
-		discord.SlashCommandCreate{
-			Name: "resume",
-			NameLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "resume",
-				discord.LocalePortugueseBR: "resumir",
-			},
-			Description: "Resumes the current track",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Resumes the current track",
-				discord.LocalePortugueseBR: "Resume a música atual",
-			},
-		},
-		discord.SlashCommandCreate{
-			Name: "skip",
-			NameLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "skip",
-				discord.LocalePortugueseBR: "pular",
-			},
-			Description: "Skips the current track",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Skips the current track",
-				discord.LocalePortugueseBR: "Pula a música atual",
-			},
-		},
-		discord.SlashCommandCreate{
-			Name: "join",
-			NameLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "join",
-				discord.LocalePortugueseBR: "entrar",
-			},
-			Description: "Joins the voice channel",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Joins the voice channel",
-				discord.LocalePortugueseBR: "Entra no canal de voz",
-			},
-		},
-		discord.SlashCommandCreate{
-			Name: "leave",
-			NameLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "leave",
-				discord.LocalePortugueseBR: "sair",
-			},
-			Description: "Leaves the voice channel",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Leaves the voice channel",
-				discord.LocalePortugueseBR: "Sai do canal de voz",
-			},
-		},
-		discord.SlashCommandCreate{
-			Name: "queue",
-			NameLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "queue",
-				discord.LocalePortugueseBR: "fila",
-			},
-			Description: "Displays the queue",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Displays the music queue",
-				discord.LocalePortugueseBR: "Exibe a fila de músicas",
-			},
-		},
-		discord.SlashCommandCreate{
-			Name: "playing",
-			NameLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "playing",
-				discord.LocalePortugueseBR: "tocando",
-			},
-			Description: "Displays the currently playing track",
-			DescriptionLocalizations: map[discord.Locale]string{
-				discord.LocaleEnglishUS:    "Displays the currently playing track",
-				discord.LocalePortugueseBR: "Exibe a música atual",
-			},
-		},
-
-		discord.SlashCommandCreate{
-			Name:        "reset",
-			Description: "Resets your chat history with the bot",
-		},
+// Reads the images in the assets directory, returning them as choices for the joel command.
+func loadAssets() ([]discord.ApplicationCommandOptionChoiceString, error) {
+	assets, err := os.ReadDir(CONFIG.Load().AssetsDir)
+	if err != nil {
+		return nil, err
 	}
+
+	paths := map[string]string{}
+	names := make([]string, 0, len(assets))
+	choices := make([]discord.ApplicationCommandOptionChoiceString, 0, 25)
+
+	for _, asset := range assets {
+		name := asset.Name()
+		name = name[:len(name)-len(filepath.Ext(name))]
+		paths[name] = filepath.Join(CONFIG.Load().AssetsDir, asset.Name())
+		names = append(names, name)
+
+		choices = append(choices, discord.ApplicationCommandOptionChoiceString{
+			Name:  name,
+			Value: name,
+		})
+	}
+
+	ASSETS_MU.Lock()
+	ASSETS_PATHS = paths
+	ASSETS = names
+	ASSETS_MU.Unlock()
+
+	return choices, nil
+}
+
+var (
+	MIN_VOLUME = 0
+	MAX_VOLUME = 200
+
+	MIN_POSITION         = 1
+	PLAYLIST_NAME_LENGTH = 32
 )
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	CATEGORY_GENERAL = "General"
+	CATEGORY_LLM     = "LLM"
+	CATEGORY_RANDOM  = "Random"
+	CATEGORY_MUSIC   = "Music"
+	CATEGORY_ADMIN   = "Admin"
+
+	FEATURE_LLM   = "llm"
+	FEATURE_MUSIC = "music"
+)
+
+var (
+	// Order of the categories in /help
+	CATEGORIES = []string{CATEGORY_GENERAL, CATEGORY_LLM, CATEGORY_RANDOM, CATEGORY_MUSIC, CATEGORY_ADMIN}
+
+	// Every command, built by NewCommands from the lists each file declares
+	COMMANDS = []Command{}
+
+	ADMIN_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name:        "admin",
+				Description: "Bot administration",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Bot administration",
+					discord.LocalePortugueseBR: "Administração do bot",
+				},
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionSubCommand{
+						Name:        "nodes",
+						Description: "Displays the status of the Lavalink nodes",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Displays the status of the Lavalink nodes",
+							discord.LocalePortugueseBR: "Exibe o estado dos nós do Lavalink",
+						},
+					},
+					discord.ApplicationCommandOptionSubCommand{
+						Name:        "reload",
+						Description: "Reloads the config file",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Reloads the config file",
+							discord.LocalePortugueseBR: "Recarrega o arquivo de configuração",
+						},
+					},
+				},
+			},
+			Subcommands: map[string]CommandHandler{
+				"nodes":  adminNodes,
+				"reload": adminReload,
+			},
+			Category:    CATEGORY_ADMIN,
+			Permissions: discord.PermissionAdministrator,
+			Examples:    []string{"/admin nodes", "/admin reload"},
+		},
+	}
+)
+
+type CommandHandler func(event *events.ApplicationCommandInteractionCreate)
+
+type Command struct {
+	Create discord.ApplicationCommandCreate
+
+	Handler CommandHandler
+	// Used instead of Handler for commands with subcommands
+	Subcommands map[string]CommandHandler
+
+	// Groups the command in /help
+	Category string
+	// Commands of disabled features are not registered, empty for commands that are always enabled
+	Feature string
+	// Not available in DMs
+	GuildOnly bool
+	// Members need all of them, also registered as the default permissions so others don't see the command.
+	// Like Discord's default permissions they don't apply in DMs
+	Permissions discord.Permissions
+	// Time a user has to wait between uses
+	Cooldown time.Duration
+
+	// Shown by /help command
+	Examples []string
+}
+
+type CooldownKey struct {
+	UserID  snowflake.ID
+	Command string
+}
+
+type Cooldowns struct {
+	// When each cooldown ends
+	store map[CooldownKey]time.Time
+	mu    sync.Mutex
+}
+
+func NewCommands() {
+	COMMANDS = slices.Concat(
+		GENERAL_COMMANDS,
+		LLM_COMMANDS,
+		FUN_COMMANDS,
+		MUSIC_COMMANDS,
+		HISTORY_COMMANDS,
+		FILTER_COMMANDS,
+		SOURCE_COMMANDS,
+		DJ_COMMANDS,
+		AUTOPLAY_COMMANDS,
+		PLAYLIST_COMMANDS,
+		ATTACHMENT_COMMANDS,
+		ADMIN_COMMANDS,
+	)
+}
+
+func (c Command) Name() string {
+	return c.Create.CommandName()
+}
+
+// Starts the cooldown, or returns how long is left if it's still running.
+func (c *Cooldowns) Start(userID snowflake.ID, command string, duration time.Duration) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	key := CooldownKey{userID, command}
+
+	end, ok := c.store[key]
+	if ok && end.After(now) {
+		return end.Sub(now)
+	}
+
+	for key, end := range c.store {
+		if !end.After(now) {
+			delete(c.store, key)
+		}
+	}
+
+	c.store[key] = now.Add(duration)
+	return 0
+}
+
+// Wraps handlers that are also used by components.
+func interactionHandler(handler func(event Interaction)) CommandHandler {
+	return func(event *events.ApplicationCommandInteractionCreate) {
+		handler(event)
+	}
+}
+
+func findCommand(name string) (Command, bool) {
+	for _, command := range COMMANDS {
+		if command.Name() == name {
+			return command, true
+		}
+	}
+
+	return Command{}, false
+}
+
+// Commands of disabled features are not registered.
+func commandEnabled(command Command) bool {
+	switch command.Feature {
+	case FEATURE_MUSIC:
+		return CONFIG.Load().Features.Music
+	case FEATURE_LLM:
+		return CONFIG.Load().Features.LLM
+	}
+
+	return true
+}
+
+// The commands to register, with the contexts and default permissions their flags ask for.
+func enabledCommands() []discord.ApplicationCommandCreate {
+	creates := make([]discord.ApplicationCommandCreate, 0, len(COMMANDS))
+
+	for _, command := range COMMANDS {
+		if !commandEnabled(command) {
+			continue
+		}
+
+		var contexts []discord.InteractionContextType
+		if command.GuildOnly {
+			contexts = []discord.InteractionContextType{discord.InteractionContextTypeGuild}
+		}
+
+		var permissions *json.Nullable[discord.Permissions]
+		if command.Permissions != 0 {
+			permissions = json.NewNullablePtr(command.Permissions)
+		}
+
+		switch create := command.Create.(type) {
+		case discord.SlashCommandCreate:
+			create.Contexts = contexts
+			create.DefaultMemberPermissions = permissions
+			creates = append(creates, create)
+		case discord.MessageCommandCreate:
+			create.Contexts = contexts
+			create.DefaultMemberPermissions = permissions
+			creates = append(creates, create)
+		default:
+			creates = append(creates, create)
+		}
+	}
+
+	return creates
+}
+
+func commandListener(event *events.ApplicationCommandInteractionCreate) {
+	name := event.Data.CommandName()
+
+	eventLogger(event).Info("Command", "username", event.User().Username)
+
+	outcome := COMMAND_OK
+	defer countCommand(name, &outcome)
+
+	command, ok := findCommand(name)
+	if !ok {
+		outcome = COMMAND_UNKNOWN
+		replyEphemeral(event, localize(event.Locale(), "command_unknown"))
+		return
+	}
+
+	if !commandEnabled(command) {
+		outcome = COMMAND_DISABLED
+		replyEphemeral(event, localize(event.Locale(), "command_disabled"))
+		return
+	}
+
+	if command.GuildOnly && event.GuildID() == nil {
+		outcome = COMMAND_REFUSED
+		replyEphemeral(event, localize(event.Locale(), "command_guild_only"))
+		return
+	}
+
+	member := event.Member()
+	if member != nil && !member.Permissions.Has(command.Permissions) {
+		outcome = COMMAND_REFUSED
+		replyEphemeral(event, localize(event.Locale(), "command_permissions"))
+		return
+	}
+
+	if command.Cooldown > 0 {
+		left := COOLDOWNS.Start(event.User().ID, name, command.Cooldown)
+		if left > 0 {
+			outcome = COMMAND_COOLDOWN
+			replyEphemeral(event, localize(event.Locale(), "command_cooldown", int(math.Ceil(left.Seconds()))))
+			return
+		}
+	}
+
+	handler := command.Handler
+
+	if command.Subcommands != nil {
+		data := event.SlashCommandInteractionData()
+		if data.SubCommandName == nil {
+			outcome = COMMAND_UNKNOWN
+			replyEphemeral(event, localize(event.Locale(), "command_subcommand"))
+			return
+		}
+
+		handler, ok = command.Subcommands[*data.SubCommandName]
+		if !ok {
+			outcome = COMMAND_UNKNOWN
+			replyEphemeral(event, localize(event.Locale(), "command_unknown"))
+			return
+		}
+	}
+
+	handler(event)
+}
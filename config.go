@@ -3,54 +3,494 @@ package main
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net"
 	"os"
+	"slices"
+	"strconv"
 
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/gateway"
+	"github.com/disgoorg/snowflake/v2"
 	"github.com/go-json-experiment/json"
 )
 
 type Config struct {
-	Token  string `json:"token"`
+	Discord  DiscordConfig  `json:"discord"`
+	Lavalink LavalinkConfig `json:"lavalink"`
+	LLM      LLMConfig      `json:"llm"`
+	Music    MusicConfig    `json:"music"`
+	Storage  StorageConfig  `json:"storage"`
+	Features FeaturesConfig `json:"features"`
+	Logging  LoggingConfig  `json:"logging"`
+	HTTP     HTTPConfig     `json:"http"`
+
+	// Images used by the joel and ttj commands
+	AssetsDir string `json:"assets_dir"`
+}
+
+type DiscordConfig struct {
+	Token string `json:"token"`
+	// Names from GATEWAY_INTENTS
+	Intents  []string       `json:"intents"`
+	Presence PresenceConfig `json:"presence"`
+	// Commands are registered to this guild instead of globally, they update instantly there
+	DevGuild snowflake.ID `json:"dev_guild"`
+}
+
+type PresenceConfig struct {
+	Text string `json:"text"`
+	// online, idle, dnd or invisible
+	Status string `json:"status"`
+}
+
+type LavalinkConfig struct {
+	Nodes []LavalinkNode `json:"nodes"`
+}
+
+type LavalinkNode struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Password string `json:"password"`
+	Secure   bool   `json:"secure"`
+	// Prefix of the Discord voice endpoints this node is preferred for, like "us-east" or "brazil"
+	Region string `json:"region"`
+}
+
+type LLMConfig struct {
+	// Base URL of an OpenAI API compatible server
+	URL    string `json:"url"`
 	Prompt string `json:"prompt"`
 }
 
+type MusicConfig struct {
+	// Minutes the bot stays in a voice channel while alone or not playing anything
+	IdleTimeout int `json:"idle_timeout"`
+
+	// Used in the role's guild while it has no DJ role set with /dj
+	DJRole snowflake.ID `json:"dj_role"`
+
+	// Percentage of listeners that need to vote to skip a track, 0 lets anyone skip right away
+	VoteSkipPercentage int `json:"vote_skip_percentage"`
+
+	Limits QueueLimits `json:"limits"`
+}
+
+type StorageConfig struct {
+	Settings  string `json:"settings"`
+	State     string `json:"state"`
+	Playlists string `json:"playlists"`
+}
+
+type LoggingConfig struct {
+	// debug, info, warn or error
+	Level string `json:"level"`
+	// text or json
+	Format string `json:"format"`
+}
+
+type HTTPConfig struct {
+	// Address the /metrics, /healthz and /readyz endpoints listen on, like ":9100", empty disables them
+	Address string `json:"address"`
+}
+
+type FeaturesConfig struct {
+	LLM   bool `json:"llm"`
+	Music bool `json:"music"`
+}
+
+// Top level keys from before the config was split into sections, still read so old files keep working.
+type LegacyConfig struct {
+	Token              *string       `json:"token"`
+	Prompt             *string       `json:"prompt"`
+	IdleTimeout        *int          `json:"idle_timeout"`
+	DJRole             *snowflake.ID `json:"dj_role"`
+	VoteSkipPercentage *int          `json:"vote_skip_percentage"`
+	Limits             *QueueLimits  `json:"limits"`
+}
+
+type ConfigFile struct {
+	Config
+	LegacyConfig
+}
+
+const (
+	DEFAULT_IDLE_TIMEOUT = 5
+
+	ENV_PREFIX = "JOEL_"
+)
+
 var (
-	ErrConfigNotFound = errors.New("config.json did not exist so a new one was created, please edit it and restart the bot")
+	DEFAULT_NODE = LavalinkNode{
+		Name:    "joel",
+		Address: "0.0.0.0:2333",
+	}
+
+	GATEWAY_INTENTS = map[string]gateway.Intents{
+		"guilds":             gateway.IntentGuilds,
+		"guild_members":      gateway.IntentGuildMembers,
+		"guild_messages":     gateway.IntentGuildMessages,
+		"guild_voice_states": gateway.IntentGuildVoiceStates,
+		"direct_messages":    gateway.IntentDirectMessages,
+		"message_content":    gateway.IntentMessageContent,
+	}
+
+	ONLINE_STATUSES = map[string]discord.OnlineStatus{
+		"online":    discord.OnlineStatusOnline,
+		"idle":      discord.OnlineStatusIdle,
+		"dnd":       discord.OnlineStatusDND,
+		"invisible": discord.OnlineStatusInvisible,
+	}
+
+	ErrConfigNotFound = errors.New("the config file did not exist so a new one was created, please edit it and restart the bot")
 )
 
+func defaultConfig() Config {
+	return Config{
+		Discord: DiscordConfig{
+			Intents: []string{"guilds", "guild_messages", "direct_messages", "message_content", "guild_voice_states"},
+			Presence: PresenceConfig{
+				Text:   "/help",
+				Status: "dnd",
+			},
+		},
+		Lavalink: LavalinkConfig{
+			Nodes: []LavalinkNode{DEFAULT_NODE},
+		},
+		LLM: LLMConfig{
+			URL:    "http://localhost:2444",
+			Prompt: "You are a helpful assistant...",
+		},
+		Music: MusicConfig{
+			IdleTimeout: DEFAULT_IDLE_TIMEOUT,
+		},
+		Storage: StorageConfig{
+			Settings:  SETTINGS_PATH,
+			State:     STATE_PATH,
+			Playlists: PLAYLISTS_PATH,
+		},
+		Features: FeaturesConfig{
+			LLM:   true,
+			Music: true,
+		},
+		Logging: LoggingConfig{
+			Level:  "info",
+			Format: LOG_FORMAT_TEXT,
+		},
+		AssetsDir: "./assets",
+	}
+}
+
+// Reads the config file over the defaults, then applies the environment overrides and validates the result.
+// Without a config file one is created, unless the token comes from the environment.
 func NewConfig() error {
-	_, err := os.Stat("config.json")
+	_, err := os.Stat(*CONFIG_PATH)
+	if os.IsNotExist(err) && os.Getenv(ENV_PREFIX+"DISCORD_TOKEN") == "" {
+		return createConfig(defaultConfig())
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	CONFIG.Store(&config)
+	return nil
+}
 
+func loadConfig() (Config, error) {
+	config := defaultConfig()
+
+	_, err := os.Stat(*CONFIG_PATH)
 	if os.IsNotExist(err) {
-		file, err := os.Create("config.json")
+		slog.Info("No config file, using the environment")
+	} else {
+		slog.Info("Reading config file", "path", *CONFIG_PATH)
+		file, err := os.ReadFile(*CONFIG_PATH)
 		if err != nil {
-			return err
-		}
-		defer file.Close()
-
-		config := Config{
-			Token:  "DISCORD_TOKEN",
-			Prompt: "You are a helpful assistant...",
+			return config, err
 		}
 
-		err = json.MarshalWrite(file, config)
+		// Unknown fields are usually typos
+		parsed := ConfigFile{Config: config}
+		err = json.Unmarshal(file, &parsed, json.RejectUnknownMembers(true))
 		if err != nil {
-			return err
+			return config, err
 		}
 
-		return ErrConfigNotFound
+		config = parsed.Config
+		applyLegacy(&config, parsed.LegacyConfig)
+	}
+
+	err = applyEnv(&config)
+	if err != nil {
+		return config, err
+	}
+
+	return config, config.Validate()
+}
+
+// Moves the old top level keys into their sections, warning about each so the file gets updated.
+func applyLegacy(config *Config, legacy LegacyConfig) {
+	moved := func(key string, section string) {
+		slog.Warn("Deprecated config key, move it to its section", "key", key, "new_key", section)
+	}
+
+	if legacy.Token != nil {
+		config.Discord.Token = *legacy.Token
+		moved("token", "discord.token")
+	}
+
+	if legacy.Prompt != nil {
+		config.LLM.Prompt = *legacy.Prompt
+		moved("prompt", "llm.prompt")
+	}
+
+	if legacy.IdleTimeout != nil {
+		config.Music.IdleTimeout = *legacy.IdleTimeout
+		moved("idle_timeout", "music.idle_timeout")
+	}
+
+	if legacy.DJRole != nil {
+		config.Music.DJRole = *legacy.DJRole
+		moved("dj_role", "music.dj_role")
+	}
+
+	if legacy.VoteSkipPercentage != nil {
+		config.Music.VoteSkipPercentage = *legacy.VoteSkipPercentage
+		moved("vote_skip_percentage", "music.vote_skip_percentage")
+	}
+
+	if legacy.Limits != nil {
+		config.Music.Limits = *legacy.Limits
+		moved("limits", "music.limits")
 	}
+}
 
-	fmt.Println("Reading config file")
-	file, err := os.ReadFile("config.json")
+func createConfig(config Config) error {
+	file, err := os.Create(*CONFIG_PATH)
 	if err != nil {
 		return err
 	}
+	defer file.Close()
+
+	config.Discord.Token = "DISCORD_TOKEN"
 
-	fmt.Println("Parsing config file")
-	err = json.Unmarshal(file, &CONFIG)
+	err = json.MarshalWrite(file, config)
 	if err != nil {
 		return err
 	}
 
-	return err
+	return ErrConfigNotFound
+}
+
+// Environment variables like JOEL_DISCORD_TOKEN override the matching config field.
+// JOEL_LAVALINK_* variables override the first node.
+func applyEnv(config *Config) error {
+	if len(config.Lavalink.Nodes) == 0 {
+		config.Lavalink.Nodes = []LavalinkNode{DEFAULT_NODE}
+	}
+
+	node := &config.Lavalink.Nodes[0]
+
+	texts := map[string]*string{
+		"DISCORD_TOKEN":           &config.Discord.Token,
+		"DISCORD_PRESENCE_TEXT":   &config.Discord.Presence.Text,
+		"DISCORD_PRESENCE_STATUS": &config.Discord.Presence.Status,
+		"LAVALINK_NAME":           &node.Name,
+		"LAVALINK_ADDRESS":        &node.Address,
+		"LAVALINK_PASSWORD":       &node.Password,
+		"LAVALINK_REGION":         &node.Region,
+		"LLM_URL":                 &config.LLM.URL,
+		"LLM_PROMPT":              &config.LLM.Prompt,
+		"STORAGE_SETTINGS":        &config.Storage.Settings,
+		"STORAGE_STATE":           &config.Storage.State,
+		"STORAGE_PLAYLISTS":       &config.Storage.Playlists,
+		"LOGGING_LEVEL":           &config.Logging.Level,
+		"LOGGING_FORMAT":          &config.Logging.Format,
+		"HTTP_ADDRESS":            &config.HTTP.Address,
+		"ASSETS_DIR":              &config.AssetsDir,
+	}
+
+	ints := map[string]*int{
+		"MUSIC_IDLE_TIMEOUT":         &config.Music.IdleTimeout,
+		"MUSIC_VOTE_SKIP_PERCENTAGE": &config.Music.VoteSkipPercentage,
+	}
+
+	bools := map[string]*bool{
+		"LAVALINK_SECURE": &node.Secure,
+		"FEATURES_LLM":    &config.Features.LLM,
+		"FEATURES_MUSIC":  &config.Features.Music,
+	}
+
+	ids := map[string]*snowflake.ID{
+		"DISCORD_DEV_GUILD": &config.Discord.DevGuild,
+		"MUSIC_DJ_ROLE":     &config.Music.DJRole,
+	}
+
+	for name, field := range texts {
+		value, ok := os.LookupEnv(ENV_PREFIX + name)
+		if ok {
+			*field = value
+		}
+	}
+
+	for name, field := range ints {
+		value, ok := os.LookupEnv(ENV_PREFIX + name)
+		if !ok {
+			continue
+		}
+
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s%s must be a number: %w", ENV_PREFIX, name, err)
+		}
+
+		*field = number
+	}
+
+	for name, field := range bools {
+		value, ok := os.LookupEnv(ENV_PREFIX + name)
+		if !ok {
+			continue
+		}
+
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s%s must be true or false: %w", ENV_PREFIX, name, err)
+		}
+
+		*field = enabled
+	}
+
+	for name, field := range ids {
+		value, ok := os.LookupEnv(ENV_PREFIX + name)
+		if !ok {
+			continue
+		}
+
+		id, err := snowflake.Parse(value)
+		if err != nil {
+			return fmt.Errorf("%s%s must be an ID: %w", ENV_PREFIX, name, err)
+		}
+
+		*field = id
+	}
+
+	return nil
+}
+
+// Returns every problem found, each naming the field it is about.
+func (c Config) Validate() error {
+	errs := []error{}
+
+	invalid := func(field string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Discord.Token == "" || c.Discord.Token == "DISCORD_TOKEN" {
+		invalid("discord.token", "is required")
+	}
+
+	for i, intent := range c.Discord.Intents {
+		_, ok := GATEWAY_INTENTS[intent]
+		if !ok {
+			invalid(fmt.Sprintf("discord.intents[%d]", i), "unknown intent %q", intent)
+		}
+	}
+
+	_, ok := ONLINE_STATUSES[c.Discord.Presence.Status]
+	if !ok {
+		invalid("discord.presence.status", "must be online, idle, dnd or invisible, got %q", c.Discord.Presence.Status)
+	}
+
+	names := []string{}
+	for i, node := range c.Lavalink.Nodes {
+		if node.Name == "" {
+			invalid(fmt.Sprintf("lavalink.nodes[%d].name", i), "is required")
+		} else if slices.Contains(names, node.Name) {
+			invalid(fmt.Sprintf("lavalink.nodes[%d].name", i), "%q is used by another node", node.Name)
+		}
+
+		if node.Address == "" {
+			invalid(fmt.Sprintf("lavalink.nodes[%d].address", i), "is required")
+		}
+
+		names = append(names, node.Name)
+	}
+
+	if c.Features.LLM && c.LLM.URL == "" {
+		invalid("llm.url", "is required when the llm feature is enabled")
+	}
+
+	if c.Music.IdleTimeout <= 0 {
+		invalid("music.idle_timeout", "must be more than 0, got %d", c.Music.IdleTimeout)
+	}
+
+	if c.Music.VoteSkipPercentage < 0 || c.Music.VoteSkipPercentage > 100 {
+		invalid("music.vote_skip_percentage", "must be between 0 and 100, got %d", c.Music.VoteSkipPercentage)
+	}
+
+	limits := map[string]int{
+		"music.limits.max_queue_size":   c.Music.Limits.MaxQueueSize,
+		"music.limits.max_track_length": c.Music.Limits.MaxTrackLength,
+		"music.limits.max_user_tracks":  c.Music.Limits.MaxUserTracks,
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(limits)) {
+		limit := limits[field]
+		if limit < 0 {
+			invalid(field, "can't be negative, got %d", limit)
+		}
+	}
+
+	storage := map[string]string{
+		"storage.settings":  c.Storage.Settings,
+		"storage.state":     c.Storage.State,
+		"storage.playlists": c.Storage.Playlists,
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(storage)) {
+		path := storage[field]
+		if path == "" {
+			invalid(field, "is required")
+		}
+	}
+
+	var level slog.Level
+	err := level.UnmarshalText([]byte(c.Logging.Level))
+	if err != nil {
+		invalid("logging.level", "must be debug, info, warn or error, got %q", c.Logging.Level)
+	}
+
+	if c.Logging.Format != LOG_FORMAT_TEXT && c.Logging.Format != LOG_FORMAT_JSON {
+		invalid("logging.format", "must be text or json, got %q", c.Logging.Format)
+	}
+
+	if c.HTTP.Address != "" {
+		_, _, err = net.SplitHostPort(c.HTTP.Address)
+		if err != nil {
+			invalid("http.address", "must be host:port, got %q", c.HTTP.Address)
+		}
+	}
+
+	// joel picks one of the assets, it needs at least one
+	assets, err := os.ReadDir(c.AssetsDir)
+	if err != nil {
+		invalid("assets_dir", "%q is not a readable directory", c.AssetsDir)
+	} else if len(assets) == 0 {
+		invalid("assets_dir", "%q has no images", c.AssetsDir)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c Config) GatewayIntents() gateway.Intents {
+	var intents gateway.Intents
+	for _, name := range c.Discord.Intents {
+		intents = intents.Add(GATEWAY_INTENTS[name])
+	}
+
+	return intents
 }
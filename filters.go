@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	FILTER_OFF = "off"
+)
+
+var (
+	FILTERS = []string{"nightcore", "vaporwave", "bassboost", "8d", "karaoke", "tremolo", "slowed", FILTER_OFF}
+
+	FILTER_PRESETS = map[string]lavalink.Filters{
+		"nightcore": {
+			Timescale: &lavalink.Timescale{Speed: 1.2, Pitch: 1.25, Rate: 1},
+		},
+		"vaporwave": {
+			Timescale: &lavalink.Timescale{Speed: 0.85, Pitch: 0.8, Rate: 1},
+			Equalizer: &lavalink.Equalizer{0: 0.3, 1: 0.3},
+			Tremolo:   &lavalink.Tremolo{Frequency: 14, Depth: 0.3},
+		},
+		"bassboost": {
+			Equalizer: &lavalink.Equalizer{0: 0.6, 1: 0.67, 2: 0.67, 3: 0.4, 4: -0.5, 5: 0.15, 6: -0.45, 7: 0.23, 8: 0.35, 9: 0.45, 10: 0.55, 11: 0.6, 12: 0.55},
+		},
+		"8d": {
+			Rotation: &lavalink.Rotation{RotationHz: 1},
+		},
+		"karaoke": {
+			Karaoke: &lavalink.Karaoke{Level: 1, MonoLevel: 1, FilterBand: 220, FilterWidth: 100},
+		},
+		"tremolo": {
+			Tremolo: &lavalink.Tremolo{Frequency: 4, Depth: 0.75},
+		},
+		"slowed": {
+			Timescale: &lavalink.Timescale{Speed: 0.8, Pitch: 0.9, Rate: 1},
+		},
+		FILTER_OFF: {},
+	}
+)
+
+// Returns the filters for the guild's active preset, "off" if none was set.
+func guildFilters(guildID snowflake.ID) (string, lavalink.Filters) {
+	name := SETTINGS.Get(guildID).Filter
+
+	filters, ok := FILTER_PRESETS[name]
+	if !ok {
+		return FILTER_OFF, lavalink.Filters{}
+	}
+
+	return name, filters
+}
+
+func filterCommand(event *events.ApplicationCommandInteractionCreate) {
+	preset, ok := event.SlashCommandInteractionData().OptString("preset")
+	if !ok {
+		reply(event, "Please provide a filter.")
+		return
+	}
+
+	filter(event, preset)
+}
+
+func filter(event *events.ApplicationCommandInteractionCreate, name string) {
+	guildID := *event.GuildID()
+
+	if !requireSameChannel(event) {
+		return
+	}
+
+	filters, ok := FILTER_PRESETS[name]
+	if !ok {
+		reply(event, fmt.Sprintf("Unknown filter `%s`.", name))
+		return
+	}
+
+	err := SETTINGS.Update(guildID, func(settings *GuildSettings) {
+		settings.Filter = name
+	})
+	if err != nil {
+		eventLogger(event).Error("Error saving settings", "err", err)
+		reply(event, err.Error())
+		return
+	}
+
+	if !TRACKS.Empty() {
+		player := CLIENT.Lavalink.Player(guildID)
+
+		err = player.Update(context.TODO(), lavalink.WithFilters(filters))
+		if err != nil {
+			eventLogger(event).Error("Error applying filter", "err", err)
+			reply(event, err.Error())
+			return
+		}
+	}
+
+	if name == FILTER_OFF {
+		reply(event, "Filters disabled.")
+		return
+	}
+
+	reply(event, fmt.Sprintf("Filter set to `%s`.", name))
+}
+
+var (
+	FILTER_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "filter",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "filter",
+					discord.LocalePortugueseBR: "filtro",
+				},
+				Description: "Applies an audio filter preset",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Applies an audio filter preset",
+					discord.LocalePortugueseBR: "Aplica um filtro de áudio",
+				},
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name: "preset",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "preset",
+							discord.LocalePortugueseBR: "predefinicao",
+						},
+						Description: "Filter preset, off disables all filters",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Filter preset, off disables all filters",
+							discord.LocalePortugueseBR: "Filtro predefinido, off desativa todos os filtros",
+						},
+						Choices:  []discord.ApplicationCommandOptionChoiceString{},
+						Required: true,
+					},
+				},
+			},
+			Handler:   filterCommand,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+			Examples:  []string{"/filter preset:nightcore", "/filter preset:off"},
+		},
+	}
+)
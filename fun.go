@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/rand/v2"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -11,18 +12,31 @@ import (
 	"github.com/disgoorg/disgo/events"
 )
 
+func joelCommand(event *events.ApplicationCommandInteractionCreate) {
+	name, _ := event.SlashCommandInteractionData().OptString("joel")
+	joel(event, name)
+}
+
 func joel(event *events.ApplicationCommandInteractionCreate, joel string) {
 	joel = strings.TrimSpace(joel)
+
+	ASSETS_MU.Lock()
 	path, ok := ASSETS_PATHS[joel]
-	if !ok || joel == "" {
-		n := rand.IntN(len(ASSETS) - 1)
-		joel = ASSETS[n]
+	if (!ok || joel == "") && len(ASSETS) > 0 {
+		joel = ASSETS[rand.IntN(len(ASSETS))]
 		path = ASSETS_PATHS[joel]
+		ok = true
+	}
+	ASSETS_MU.Unlock()
+
+	if !ok {
+		replyEphemeral(event, "There are no images to post.")
+		return
 	}
 
 	image, err := os.Open(path)
 	if err != nil {
-		fmt.Printf("Error opening file: %v\n", err)
+		eventLogger(event).Error("Error opening file", "err", err)
 		return
 	}
 	defer image.Close()
@@ -35,18 +49,18 @@ func ttj(event *events.ApplicationCommandInteractionCreate) {
 	now := time.Now()
 	err := event.DeferCreateMessage(false)
 	if err != nil {
-		fmt.Printf("Error deferring message: %v\n", err)
+		eventLogger(event).Error("Error deferring message", "err", err)
 		reply(event, "An error occurred while deferring the message.")
 		return
 	}
 	since := time.Until(now)
 
 	ttj := fmt.Sprintf("TTJ took %dms", since.Abs().Milliseconds())
-	fmt.Println(ttj)
+	eventLogger(event).Info("TTJ", "ms", since.Abs().Milliseconds())
 
-	image, err := os.Open("./assets/Joel.webp")
+	image, err := os.Open(filepath.Join(CONFIG.Load().AssetsDir, "Joel.webp"))
 	if err != nil {
-		fmt.Printf("Error opening file: %v\n", err)
+		eventLogger(event).Error("Error opening file", "err", err)
 		return
 	}
 	defer image.Close()
@@ -54,6 +68,43 @@ func ttj(event *events.ApplicationCommandInteractionCreate) {
 	message := discord.NewMessageCreateBuilder().SetContent(ttj).AddFile(image.Name(), image.Name(), image).Build()
 	_, err = CLIENT.Rest.CreateFollowupMessage(event.ApplicationID(), event.Token(), message)
 	if err != nil {
-		fmt.Printf("Error replying: %v\n", err)
+		eventLogger(event).Error("Error replying", "err", err)
 	}
 }
+
+var (
+	FUN_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name:        "joel",
+				Description: "JOEL",
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name:                     "",
+						Description:              "",
+						DescriptionLocalizations: map[discord.Locale]string{},
+						Choices:                  []discord.ApplicationCommandOptionChoiceString{},
+						Required:                 false,
+					},
+				},
+			},
+			Handler:  joelCommand,
+			Category: CATEGORY_RANDOM,
+			Cooldown: 3 * time.Second,
+			Examples: []string{"/joel", "/joel joel:<name>"},
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name:        "ttj",
+				Description: "Latency test",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Latency test",
+					discord.LocalePortugueseBR: "Teste de latência",
+				},
+			},
+			Handler:  ttj,
+			Category: CATEGORY_RANDOM,
+			Cooldown: 5 * time.Second,
+		},
+	}
+)
@@ -5,13 +5,13 @@ go 1.23
 require (
 	github.com/disgoorg/disgo v0.18.14
 	github.com/disgoorg/disgolink/v3 v3.0.3
+	github.com/disgoorg/json v1.2.0
 	github.com/disgoorg/snowflake/v2 v2.0.3
 	github.com/dslipak/pdf v0.0.2
 	github.com/go-json-experiment/json v0.0.0-20250108213703-50a1ba2c17a5
 )
 
 require (
-	github.com/disgoorg/json v1.2.0 // indirect
 	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/sasha-s/go-csync v0.0.0-20240107134140-fcbab37b09ad // indirect
 	golang.org/x/crypto v0.31.0 // indirect
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// Runtime state of a guild's music session, lost on restart.
+type GuildState struct {
+	// Users who voted to skip the current track
+	SkipVotes []snowflake.ID
+
+	// Finished tracks, oldest first
+	History []HistoryEntry
+
+	// Channel of the last music command, used for messages not tied to an interaction
+	TextChannelID snowflake.ID
+
+	IdleTimer  *time.Timer
+	IdlePaused bool
+
+	// Voice connection, needed to move the player to another node
+	VoiceSessionID string
+	VoiceToken     string
+	VoiceEndpoint  string
+
+	// Now playing panel
+	PanelChannelID snowflake.ID
+	PanelMessageID snowflake.ID
+}
+
+type Guilds struct {
+	store map[snowflake.ID]GuildState
+	mu    sync.Mutex
+}
+
+func (g *Guilds) Get(guildID snowflake.ID) GuildState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.store[guildID]
+}
+
+func (g *Guilds) Update(guildID snowflake.ID, update func(state *GuildState)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	state := g.store[guildID]
+	update(&state)
+	g.store[guildID] = state
+}
@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
 
-	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgolink/v3/disgolink"
 	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
 )
 
 var (
@@ -15,66 +18,54 @@ var (
 )
 
 func onReady(event *events.Ready) {
-	fmt.Println("Bot is connected")
+	slog.Info("Bot is connected")
 	READY <- true
 }
 
-func commandListener(event *events.ApplicationCommandInteractionCreate) {
-	data := event.SlashCommandInteractionData()
-	command := data.CommandName()
+func componentListener(event *events.ComponentInteractionCreate) {
+	customID := event.Data.CustomID()
 
-	fmt.Printf("Command %s by %s\n", command, event.User().Username)
+	eventLogger(event).Info("Component", "username", event.User().Username)
 
-	switch command {
-	case "help":
-		help := "**LLM**:\n`reset`: Resets the users chat history with the bot\n**Random**:\n`joel`: Posts a random or specific joel if a parameter is provided\n`ttj`: Posts Time to Joel (latency test)\n**Music**:\n`play`: Plays a song, accepts an URL or a search query\n`stop`: Stops the current song\n`pause`: Pauses the current song\n`resume`: Resumes the current song\n`skip`: Skips the current song\n`join`: Joins the voice channel\n`leave`: Leaves the voice channel\n`queue`: Shows the queue\n`playing`: Shows the current song"
-		reply(event, help)
+	name, arg, _ := strings.Cut(customID, ":")
 
-	case "joel":
-		opt, _ := data.OptString("joel")
-
-		joel(event, opt)
-	case "ttj":
-		ttj(event)
-	case "play":
-		query, ok := data.OptString("query")
-		if !ok {
-			reply(event, "Please provide a query.")
+	switch name {
+	case "queue":
+		page, err := strconv.Atoi(arg)
+		if err != nil {
+			eventLogger(event).Error("Error parsing queue page", "err", err)
 			return
 		}
 
-		play(event, query)
-	case "stop":
-		stop(event)
-	case "pause":
-		pause(event)
-	case "resume":
-		resume(event)
-	case "skip":
-		skip(event)
-	case "join":
-		join(event)
-	case "leave":
-		leave(event)
-	case "queue":
-		queue(event)
-	case "playing":
-		playing(event)
-
-	case "reset":
-		reset(event)
+		queuePage(event, page)
 
-	default:
-		message := discord.NewMessageCreateBuilder().SetContent("Unknown command, please use `/help` for a list of commands.").SetEphemeral(true).Build()
-		err := event.CreateMessage(message)
+	case "history":
+		page, err := strconv.Atoi(arg)
 		if err != nil {
-			fmt.Printf("Error responding to command: %v\n", err)
+			eventLogger(event).Error("Error parsing history page", "err", err)
+			return
+		}
+
+		historyPage(event, page)
+
+	case "player":
+		switch arg {
+		case "pause":
+			pause(event)
+		case "resume":
+			resume(event)
+		case "skip":
+			skip(event)
+		case "stop":
+			stop(event)
+		case "queue":
+			queue(event)
 		}
 	}
 }
 
 func onMessageCreate(event *events.MessageCreate) {
-	if event.Message.Author.Bot {
+	if event.Message.Author.Bot || !CONFIG.Load().Features.LLM {
 		return
 	}
 
@@ -83,41 +74,207 @@ func onMessageCreate(event *events.MessageCreate) {
 }
 
 func onVoiceStateUpdate(event *events.GuildVoiceStateUpdate) {
-	CLIENT.Lavalink.OnVoiceStateUpdate(context.TODO(), event.VoiceState.GuildID, event.VoiceState.ChannelID, event.VoiceState.SessionID)
+	if !CONFIG.Load().Features.Music {
+		return
+	}
+
+	if event.VoiceState.UserID == CLIENT.Bot.ID() {
+		guildID := event.VoiceState.GuildID
+
+		// New players go to a connected node instead of whatever BestNode returns
+		if event.VoiceState.ChannelID != nil && CLIENT.Lavalink.ExistingPlayer(guildID) == nil {
+			node := healthyNode(guildID)
+			if node != nil {
+				CLIENT.Lavalink.PlayerOnNode(node, guildID)
+			}
+		}
+
+		GUILDS.Update(guildID, func(state *GuildState) {
+			state.VoiceSessionID = event.VoiceState.SessionID
+		})
+
+		CLIENT.Lavalink.OnVoiceStateUpdate(context.TODO(), guildID, event.VoiceState.ChannelID, event.VoiceState.SessionID)
+	}
+
+	// Someone joined or left a channel, the bot might be alone now
+	checkIdle(event.VoiceState.GuildID)
 }
 
 func onVoiceServerUpdate(event *events.VoiceServerUpdate) {
+	if !CONFIG.Load().Features.Music {
+		return
+	}
+
+	GUILDS.Update(event.GuildID, func(state *GuildState) {
+		state.VoiceToken = event.Token
+		state.VoiceEndpoint = *event.Endpoint
+	})
+
 	CLIENT.Lavalink.OnVoiceServerUpdate(context.TODO(), event.GuildID, event.Token, *event.Endpoint)
 }
 
+func onTrackStart(player disgolink.Player, event lavalink.TrackStartEvent) {
+	saveState(player.GuildID())
+	refreshNowPlaying(player.GuildID())
+	checkIdle(player.GuildID())
+}
+
+func onPlayerPause(player disgolink.Player, event lavalink.PlayerPauseEvent) {
+	refreshNowPlaying(player.GuildID())
+}
+
+func onPlayerResume(player disgolink.Player, event lavalink.PlayerResumeEvent) {
+	refreshNowPlaying(player.GuildID())
+}
+
 func onTrackEnd(player disgolink.Player, event lavalink.TrackEndEvent) {
-	fmt.Printf("Track ended: %s | %s\n", event.Track.Info.Title, *event.Track.Info.URI)
+	trackLogger(player.GuildID(), event.Track).Info("Track ended", "reason", event.Reason)
+
+	// The track was replaced by a retry or /previous, the queue stays as is
+	if event.Reason == lavalink.TrackEndReasonReplaced {
+		return
+	}
+
+	defer refreshNowPlaying(player.GuildID())
+	defer saveState(player.GuildID())
+
+	GUILDS.Update(player.GuildID(), func(state *GuildState) {
+		state.SkipVotes = nil
+	})
 
 	if TRACKS.Empty() {
-		fmt.Println("No more tracks to play")
+		guildLogger(player.GuildID()).Info("No more tracks to play")
 		return
 	}
 
 	user := UserInfo{}
 	err := event.Track.UserData.Unmarshal(&user)
 	if err != nil {
-		fmt.Printf("Error scanning user: %v\n", err)
+		trackLogger(player.GuildID(), event.Track).Error("Error scanning user", "err", err)
 		return
 	}
 
-	TRACKS.Pop()
+	if event.Reason == lavalink.TrackEndReasonLoadFailed {
+		if !user.Retried {
+			retryTrack(player, event.Track)
+			return
+		}
+
+		notifyTrackChannel(event.Track, fmt.Sprintf("Could not play %s, skipping it.", trackTitle(event.Track)))
+	}
+
+	track := clearPlayFlags(player.GuildID(), TRACKS.Pop())
+	addHistory(player.GuildID(), track)
 
 	if TRACKS.Empty() {
-		fmt.Println("No more tracks to play")
+		if SETTINGS.Get(player.GuildID()).Autoplay && event.Reason.MayStartNext() && autoplay(player, event.Track) {
+			return
+		}
+
+		guildLogger(player.GuildID()).Info("No more tracks to play")
+		checkIdle(player.GuildID())
 		return
 	}
 
-	if user.Skipped {
+	_, filters := guildFilters(player.GuildID())
+
+	err = player.Update(context.TODO(), lavalink.WithTrack(TRACKS.First()), lavalink.WithFilters(filters))
+	if err != nil {
+		trackLogger(player.GuildID(), event.Track).Error("Error playing next track", "err", err)
+	}
+}
+
+func onTrackException(player disgolink.Player, event lavalink.TrackExceptionEvent) {
+	logger := trackLogger(player.GuildID(), event.Track).With("severity", event.Exception.Severity, "message", event.Exception.Message)
+
+	if event.Exception.Cause != nil {
+		logger = logger.With("cause", *event.Exception.Cause)
+	}
+
+	logger.Error("Track exception")
+
+	notifyTrackChannel(event.Track, fmt.Sprintf("Error playing %s: %s", trackTitle(event.Track), event.Exception.Message))
+}
+
+func onTrackStuck(player disgolink.Player, event lavalink.TrackStuckEvent) {
+	trackLogger(player.GuildID(), event.Track).Warn("Track stuck", "threshold", event.Threshold)
+
+	user := UserInfo{}
+	err := event.Track.UserData.Unmarshal(&user)
+	if err != nil {
+		trackLogger(player.GuildID(), event.Track).Error("Error scanning user", "err", err)
+		return
+	}
+
+	if !user.Retried {
+		notifyTrackChannel(event.Track, fmt.Sprintf("%s got stuck, retrying.", trackTitle(event.Track)))
+		retryTrack(player, event.Track)
+		return
+	}
+
+	notifyTrackChannel(event.Track, fmt.Sprintf("%s got stuck again, skipping it.", trackTitle(event.Track)))
+
+	// Ending the track moves the queue forward in onTrackEnd
+	err = player.Update(context.TODO(), lavalink.WithNullTrack())
+	if err != nil {
+		trackLogger(player.GuildID(), event.Track).Error("Error stopping stuck track", "err", err)
+	}
+}
+
+func onWebSocketClosed(player disgolink.Player, event lavalink.WebSocketClosedEvent) {
+	guildLogger(player.GuildID()).Warn("Voice websocket closed", "code", event.Code, "reason", event.Reason, "by_remote", event.ByRemote)
+
+	if TRACKS.Empty() {
+		return
+	}
+
+	notifyTrackChannel(TRACKS.First(), fmt.Sprintf("Lost connection to the voice channel (%d: %s).", event.Code, event.Reason))
+}
+
+// Plays the track again from the start, marking it so it is only retried once.
+func retryTrack(player disgolink.Player, track lavalink.Track) {
+	user := UserInfo{}
+	err := track.UserData.Unmarshal(&user)
+	if err != nil {
+		guildLogger(player.GuildID()).Error("Error scanning user", "err", err)
 		return
 	}
 
-	err = player.Update(context.TODO(), lavalink.WithTrack(TRACKS.First()))
+	user.Retried = true
+
+	retried, err := track.WithUserData(user)
 	if err != nil {
-		fmt.Printf("Error playing next track: %v\n", err)
+		guildLogger(player.GuildID()).Error("Error adding retried user data", "err", err)
+		return
+	}
+
+	trackLogger(player.GuildID(), track).Info("Retrying track")
+
+	if !TRACKS.Empty() {
+		TRACKS.Replace(0, retried)
 	}
+
+	err = player.Update(context.TODO(), lavalink.WithTrack(retried))
+	if err != nil {
+		guildLogger(player.GuildID()).Error("Error retrying track", "err", err)
+	}
+}
+
+// Removes the flags that only apply to the current play, so they don't follow the track into the history.
+func clearPlayFlags(guildID snowflake.ID, track lavalink.Track) lavalink.Track {
+	user := UserInfo{}
+	err := track.UserData.Unmarshal(&user)
+	if err != nil || !user.Retried {
+		return track
+	}
+
+	user.Retried = false
+
+	cleared, err := track.WithUserData(user)
+	if err != nil {
+		guildLogger(guildID).Error("Error clearing user data", "err", err)
+		return track
+	}
+
+	return cleared
 }
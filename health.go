@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/disgoorg/disgo/gateway"
+	"github.com/disgoorg/disgolink/v3/disgolink"
+	"github.com/go-json-experiment/json"
+)
+
+const (
+	HEALTH_OK       = "ok"
+	HEALTH_FAILING  = "failing"
+	HEALTH_DISABLED = "disabled"
+)
+
+type HealthCheck struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+func checkGateway() HealthCheck {
+	if !CLIENT.Bot.HasGateway() {
+		return HealthCheck{Status: HEALTH_FAILING, Detail: "no gateway"}
+	}
+
+	status := CLIENT.Bot.Gateway().Status()
+	if status != gateway.StatusReady {
+		return HealthCheck{Status: HEALTH_FAILING, Detail: status.String()}
+	}
+
+	return HealthCheck{Status: HEALTH_OK, Detail: status.String()}
+}
+
+// At least one node has to be connected, players are moved away from the others.
+func checkLavalink() HealthCheck {
+	if CLIENT.Lavalink == nil {
+		return HealthCheck{Status: HEALTH_DISABLED}
+	}
+
+	nodes := 0
+	connected := 0
+	CLIENT.Lavalink.ForNodes(func(node disgolink.Node) {
+		nodes++
+		if node.Status() == disgolink.StatusConnected {
+			connected++
+		}
+	})
+
+	detail := fmt.Sprintf("%d of %d nodes connected", connected, nodes)
+	if connected == 0 {
+		return HealthCheck{Status: HEALTH_FAILING, Detail: detail}
+	}
+
+	return HealthCheck{Status: HEALTH_OK, Detail: detail}
+}
+
+func checkLLM() HealthCheck {
+	if !CONFIG.Load().Features.LLM {
+		return HealthCheck{Status: HEALTH_DISABLED}
+	}
+
+	status, err := pingLLMServer()
+	if err != nil {
+		return HealthCheck{Status: HEALTH_FAILING, Detail: err.Error()}
+	}
+
+	if status != http.StatusOK {
+		return HealthCheck{Status: HEALTH_FAILING, Detail: fmt.Sprintf("status %d", status)}
+	}
+
+	return HealthCheck{Status: HEALTH_OK}
+}
+
+// Responds with 503 if any check is failing.
+func writeHealth(w http.ResponseWriter, checks map[string]HealthCheck) {
+	report := HealthReport{
+		Status: HEALTH_OK,
+		Checks: checks,
+	}
+
+	code := http.StatusOK
+	for _, check := range checks {
+		if check.Status == HEALTH_FAILING {
+			report.Status = HEALTH_FAILING
+			code = http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_ = json.MarshalWrite(w, report, json.Deterministic(true))
+}
+
+// Liveness, only the gateway, a bot that lost it can't answer anything.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, map[string]HealthCheck{
+		"gateway": checkGateway(),
+	})
+}
+
+// Readiness, also checks the services the commands depend on.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	shutdown := HealthCheck{Status: HEALTH_OK}
+
+	select {
+	case <-LLM_STOP:
+		shutdown = HealthCheck{Status: HEALTH_FAILING, Detail: "shutting down"}
+	default:
+	}
+
+	writeHealth(w, map[string]HealthCheck{
+		"gateway":  checkGateway(),
+		"lavalink": checkLavalink(),
+		"llm":      checkLLM(),
+		"shutdown": shutdown,
+	})
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+)
+
+const (
+	// Discord's limit of autocomplete choices
+	MAX_AUTOCOMPLETE_CHOICES = 25
+)
+
+var (
+	GENERAL_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "help",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "help",
+					discord.LocalePortugueseBR: "ajuda",
+				},
+				Description: "Displays the help message",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Displays the help message",
+					discord.LocalePortugueseBR: "Exibe a mensagem de ajuda",
+				},
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name: "command",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "command",
+							discord.LocalePortugueseBR: "comando",
+						},
+						Description: "Shows the options, examples and requirements of a command",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Shows the options, examples and requirements of a command",
+							discord.LocalePortugueseBR: "Mostra as opções, exemplos e requisitos de um comando",
+						},
+						Autocomplete: true,
+					},
+				},
+			},
+			Handler:  help,
+			Category: CATEGORY_GENERAL,
+			Examples: []string{"/help", "/help command:play"},
+		},
+	}
+)
+
+// Returns the text in the locale, or the default text if it has no translation.
+func localized(localizations map[discord.Locale]string, locale discord.Locale, text string) string {
+	translation, ok := localizations[locale]
+	if ok && translation != "" {
+		return translation
+	}
+
+	return text
+}
+
+func commandDisplayName(command Command, locale discord.Locale) string {
+	switch create := command.Create.(type) {
+	case discord.SlashCommandCreate:
+		return "/" + localized(create.NameLocalizations, locale, create.Name)
+	case discord.MessageCommandCreate:
+		return localized(create.NameLocalizations, locale, create.Name)
+	}
+
+	return command.Name()
+}
+
+func commandDescription(command Command, locale discord.Locale) string {
+	switch create := command.Create.(type) {
+	case discord.SlashCommandCreate:
+		return localized(create.DescriptionLocalizations, locale, create.Description)
+	case discord.MessageCommandCreate:
+		return localize(locale, "help_message_command")
+	}
+
+	return ""
+}
+
+// Lines describing the options, subcommands list their own options indented under them.
+func optionLines(options []discord.ApplicationCommandOption, locale discord.Locale, indent string) []string {
+	lines := []string{}
+
+	for _, option := range options {
+		var name, description string
+		var names, descriptions map[discord.Locale]string
+		var required bool
+		var suboptions []discord.ApplicationCommandOption
+
+		switch option := option.(type) {
+		case discord.ApplicationCommandOptionSubCommand:
+			name, names = option.Name, option.NameLocalizations
+			description, descriptions = option.Description, option.DescriptionLocalizations
+			suboptions = option.Options
+		case discord.ApplicationCommandOptionString:
+			name, names = option.Name, option.NameLocalizations
+			description, descriptions = option.Description, option.DescriptionLocalizations
+			required = option.Required
+		case discord.ApplicationCommandOptionInt:
+			name, names = option.Name, option.NameLocalizations
+			description, descriptions = option.Description, option.DescriptionLocalizations
+			required = option.Required
+		case discord.ApplicationCommandOptionRole:
+			name, names = option.Name, option.NameLocalizations
+			description, descriptions = option.Description, option.DescriptionLocalizations
+			required = option.Required
+		case discord.ApplicationCommandOptionAttachment:
+			name, names = option.Name, option.NameLocalizations
+			description, descriptions = option.Description, option.DescriptionLocalizations
+			required = option.Required
+		default:
+			name = option.OptionName()
+		}
+
+		line := fmt.Sprintf("%s`%s`: %s", indent, localized(names, locale, name), localized(descriptions, locale, description))
+		if required {
+			line += " " + localize(locale, "help_required")
+		}
+
+		lines = append(lines, line)
+		lines = append(lines, optionLines(suboptions, locale, indent+"- ")...)
+	}
+
+	return lines
+}
+
+func commandRequirements(command Command, locale discord.Locale) []string {
+	requirements := []string{}
+
+	if command.GuildOnly {
+		requirements = append(requirements, localize(locale, "help_guild_only"))
+	}
+
+	if command.Permissions != 0 {
+		requirements = append(requirements, localize(locale, "help_permissions", command.Permissions.String()))
+	}
+
+	if command.Cooldown > 0 {
+		requirements = append(requirements, localize(locale, "help_cooldown", int(command.Cooldown.Seconds())))
+	}
+
+	return requirements
+}
+
+// Lists the enabled commands by category, in the description since fields are too short for the music commands.
+func helpEmbed(locale discord.Locale) discord.Embed {
+	sections := []string{localize(locale, "help_description")}
+
+	for _, category := range CATEGORIES {
+		lines := []string{"**" + localize(locale, "category_"+strings.ToLower(category)) + "**"}
+
+		for _, command := range COMMANDS {
+			if command.Category != category || !commandEnabled(command) {
+				continue
+			}
+
+			lines = append(lines, fmt.Sprintf("`%s`: %s", commandDisplayName(command, locale), commandDescription(command, locale)))
+		}
+
+		if len(lines) > 1 {
+			sections = append(sections, strings.Join(lines, "\n"))
+		}
+	}
+
+	return discord.NewEmbedBuilder().
+		SetTitle(localize(locale, "help_title")).
+		SetDescription(strings.Join(sections, "\n\n")).
+		Build()
+}
+
+func commandHelpEmbed(command Command, locale discord.Locale) discord.Embed {
+	embed := discord.NewEmbedBuilder().
+		SetTitle(commandDisplayName(command, locale)).
+		SetDescription(commandDescription(command, locale))
+
+	create, ok := command.Create.(discord.SlashCommandCreate)
+	if ok && len(create.Options) > 0 {
+		embed.AddField(localize(locale, "help_options"), strings.Join(optionLines(create.Options, locale, ""), "\n"), false)
+	}
+
+	if len(command.Examples) > 0 {
+		examples := make([]string, 0, len(command.Examples))
+		for _, example := range command.Examples {
+			examples = append(examples, "`"+example+"`")
+		}
+
+		embed.AddField(localize(locale, "help_examples"), strings.Join(examples, "\n"), false)
+	}
+
+	requirements := commandRequirements(command, locale)
+	if len(requirements) > 0 {
+		embed.AddField(localize(locale, "help_requirements"), strings.Join(requirements, "\n"), false)
+	}
+
+	return embed.Build()
+}
+
+// Finds a command by its name in English or the user's language, for names typed without picking a suggestion.
+func findLocalizedCommand(name string, locale discord.Locale) (Command, bool) {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "/"))
+
+	for _, command := range COMMANDS {
+		localName := strings.TrimPrefix(commandDisplayName(command, locale), "/")
+		if strings.ToLower(command.Name()) == name || strings.ToLower(localName) == name {
+			return command, true
+		}
+	}
+
+	return Command{}, false
+}
+
+func help(event *events.ApplicationCommandInteractionCreate) {
+	locale := event.Locale()
+	embed := helpEmbed(locale)
+
+	name, ok := event.SlashCommandInteractionData().OptString("command")
+	if ok {
+		command, ok := findLocalizedCommand(name, locale)
+		if !ok || !commandEnabled(command) {
+			replyEphemeral(event, localize(locale, "help_unknown", name))
+			return
+		}
+
+		embed = commandHelpEmbed(command, locale)
+	}
+
+	message := discord.NewMessageCreateBuilder().SetEmbeds(embed).Build()
+	sendMessage(event, message)
+}
+
+// Suggests the enabled commands whose name, in English or the user's language, contains what was typed.
+func helpAutocomplete(event *events.AutocompleteInteractionCreate) {
+	locale := event.Locale()
+	typed := strings.ToLower(strings.TrimPrefix(event.Data.String("command"), "/"))
+
+	choices := []discord.AutocompleteChoice{}
+
+	for _, command := range COMMANDS {
+		if !commandEnabled(command) {
+			continue
+		}
+
+		name := strings.TrimPrefix(commandDisplayName(command, locale), "/")
+		if !strings.Contains(strings.ToLower(command.Name()), typed) && !strings.Contains(strings.ToLower(name), typed) {
+			continue
+		}
+
+		choices = append(choices, discord.AutocompleteChoiceString{
+			Name:  name,
+			Value: command.Name(),
+		})
+
+		if len(choices) == MAX_AUTOCOMPLETE_CHOICES {
+			break
+		}
+	}
+
+	err := event.AutocompleteResult(choices)
+	if err != nil {
+		slog.Error("Error sending autocomplete choices", "user_id", event.User().ID, "err", err)
+	}
+}
+
+func autocompleteListener(event *events.AutocompleteInteractionCreate) {
+	switch event.Data.CommandName {
+	case "help":
+		helpAutocomplete(event)
+	}
+}
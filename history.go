@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	HISTORY_SIZE      = 50
+	HISTORY_PAGE_SIZE = 10
+)
+
+type HistoryEntry struct {
+	Track     lavalink.Track
+	Requester string
+	PlayedAt  time.Time
+}
+
+// Adds a finished track to the guild's history, dropping the oldest entry when full.
+func addHistory(guildID snowflake.ID, track lavalink.Track) {
+	user := UserInfo{}
+	err := track.UserData.Unmarshal(&user)
+	if err != nil {
+		guildLogger(guildID).Error("Error scanning user", "err", err)
+	}
+
+	entry := HistoryEntry{
+		Track:     track,
+		Requester: user.Username,
+		PlayedAt:  time.Now(),
+	}
+
+	GUILDS.Update(guildID, func(state *GuildState) {
+		state.History = append(state.History, entry)
+		if len(state.History) > HISTORY_SIZE {
+			state.History = state.History[len(state.History)-HISTORY_SIZE:]
+		}
+	})
+}
+
+func historyEmbed(guildID snowflake.ID, page int) (discord.Embed, int) {
+	history := GUILDS.Get(guildID).History
+
+	pages := max(1, (len(history)+HISTORY_PAGE_SIZE-1)/HISTORY_PAGE_SIZE)
+	page = max(0, min(page, pages-1))
+
+	entries := make([]string, 0, HISTORY_PAGE_SIZE)
+
+	// Most recent first
+	start := page * HISTORY_PAGE_SIZE
+	for i := start; i < min(start+HISTORY_PAGE_SIZE, len(history)); i++ {
+		entry := history[len(history)-1-i]
+		entries = append(entries, fmt.Sprintf("`%d.` %s - %s | %s <t:%d:R>", i+1, trackLink(entry.Track), entry.Track.Info.Author, entry.Requester, entry.PlayedAt.Unix()))
+	}
+
+	if len(entries) == 0 {
+		entries = append(entries, "No tracks played yet.")
+	}
+
+	embed := discord.NewEmbedBuilder().
+		SetTitle("History").
+		SetDescription(strings.Join(entries, "\n")).
+		SetFooter(fmt.Sprintf("Page %d/%d", page+1, pages), "").
+		Build()
+
+	return embed, pages
+}
+
+func historyButtons(page int, pages int) []discord.InteractiveComponent {
+	previous := discord.NewSecondaryButton("Previous", fmt.Sprintf("history:%d", page-1)).WithDisabled(page <= 0)
+	next := discord.NewSecondaryButton("Next", fmt.Sprintf("history:%d", page+1)).WithDisabled(page >= pages-1)
+
+	return []discord.InteractiveComponent{previous, next}
+}
+
+func history(event *events.ApplicationCommandInteractionCreate) {
+	embed, pages := historyEmbed(*event.GuildID(), 0)
+
+	message := discord.NewMessageCreateBuilder().
+		SetEmbeds(embed).
+		AddActionRow(historyButtons(0, pages)...).
+		Build()
+
+	sendMessage(event, message)
+}
+
+func historyPage(event *events.ComponentInteractionCreate, page int) {
+	embed, pages := historyEmbed(*event.GuildID(), page)
+	page = max(0, min(page, pages-1))
+
+	message := discord.NewMessageUpdateBuilder().
+		SetEmbeds(embed).
+		SetContainerComponents(discord.NewActionRow(historyButtons(page, pages)...)).
+		Build()
+
+	err := event.UpdateMessage(message)
+	if err != nil {
+		eventLogger(event).Error("Error updating history", "err", err)
+	}
+}
+
+// Plays the last track in the history again, the current track is kept right after it.
+func previous(event *events.ApplicationCommandInteractionCreate) {
+	guildID := *event.GuildID()
+
+	if !requireSameChannel(event) {
+		return
+	}
+
+	voice := getBotVoiceState(event)
+	if voice == nil {
+		reply(event, "The bot is not in a voice channel.")
+		return
+	}
+
+	var track lavalink.Track
+	found := false
+
+	GUILDS.Update(guildID, func(state *GuildState) {
+		if len(state.History) == 0 {
+			return
+		}
+
+		track = state.History[len(state.History)-1].Track
+		state.History = state.History[:len(state.History)-1]
+		found = true
+	})
+
+	if !found {
+		reply(event, "No previous tracks.")
+		return
+	}
+
+	player := CLIENT.Lavalink.Player(guildID)
+	playing := !TRACKS.Empty()
+
+	TRACKS.Insert(0, track)
+
+	opts := []lavalink.PlayerUpdateOpt{lavalink.WithTrack(track)}
+	if !playing {
+		_, filters := guildFilters(guildID)
+		opts = append(opts, lavalink.WithVolume(SETTINGS.Get(guildID).Volume), lavalink.WithFilters(filters))
+	}
+
+	err := player.Update(context.TODO(), opts...)
+	if err != nil {
+		eventLogger(event).Error("Error playing previous track", "err", err)
+		reply(event, err.Error())
+		return
+	}
+
+	reply(event, fmt.Sprintf("Playing previous track: %s", trackTitle(track)))
+}
+
+func replay(event *events.ApplicationCommandInteractionCreate) {
+	guildID := *event.GuildID()
+
+	if !requireSameChannel(event) {
+		return
+	}
+
+	if TRACKS.Empty() {
+		reply(event, "No tracks currently playing.")
+		return
+	}
+
+	track := TRACKS.First()
+	if track.Info.IsStream {
+		reply(event, "Streams can't be replayed.")
+		return
+	}
+
+	player := CLIENT.Lavalink.Player(guildID)
+
+	err := player.Update(context.TODO(), lavalink.WithPosition(0))
+	if err != nil {
+		eventLogger(event).Error("Error replaying track", "err", err)
+		reply(event, err.Error())
+		return
+	}
+
+	reply(event, fmt.Sprintf("Replaying %s.", trackTitle(track)))
+}
+
+var (
+	HISTORY_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "history",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "history",
+					discord.LocalePortugueseBR: "historico",
+				},
+				Description: "Displays the recently played tracks",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Displays the recently played tracks",
+					discord.LocalePortugueseBR: "Exibe as músicas tocadas recentemente",
+				},
+			},
+			Handler:   history,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "previous",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "previous",
+					discord.LocalePortugueseBR: "anterior",
+				},
+				Description: "Plays the previous track",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Plays the previous track",
+					discord.LocalePortugueseBR: "Toca a música anterior",
+				},
+			},
+			Handler:   previous,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "replay",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "replay",
+					discord.LocalePortugueseBR: "reiniciar",
+				},
+				Description: "Restarts the current track",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Restarts the current track",
+					discord.LocalePortugueseBR: "Reinicia a música atual",
+				},
+			},
+			Handler:   replay,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+	}
+)
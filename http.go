@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+var (
+	// Nil when no address is configured
+	HTTP_SERVER *http.Server
+)
+
+// Starts the HTTP server for the metrics and health endpoints, if an address is configured.
+func NewHTTPServer() {
+	address := CONFIG.Load().HTTP.Address
+	if address == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", metricsHandler)
+	mux.HandleFunc("GET /healthz", healthzHandler)
+	mux.HandleFunc("GET /readyz", readyzHandler)
+
+	HTTP_SERVER = &http.Server{
+		Addr:    address,
+		Handler: mux,
+	}
+
+	go func() {
+		slog.Info("Starting HTTP server", "address", address)
+
+		err := HTTP_SERVER.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Error running HTTP server", "err", err)
+		}
+	}()
+}
+
+func closeHTTPServer(ctx context.Context) {
+	if HTTP_SERVER == nil {
+		return
+	}
+
+	err := HTTP_SERVER.Shutdown(ctx)
+	if err != nil {
+		slog.Error("Error closing HTTP server", "err", err)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+)
+
+type QueueLimits struct {
+	// A limit of 0 disables it
+	MaxQueueSize int `json:"max_queue_size"`
+	// In minutes
+	MaxTrackLength int `json:"max_track_length"`
+	MaxUserTracks  int `json:"max_user_tracks"`
+
+	BlockStreams     bool `json:"block_streams"`
+	RejectDuplicates bool `json:"reject_duplicates"`
+}
+
+// Splits the tracks into the ones that fit the queue limits and the refusal messages for the rest.
+func checkLimits(event Interaction, tracks []lavalink.Track) ([]lavalink.Track, []string) {
+	limits := CONFIG.Load().Music.Limits
+	locale := event.Locale()
+	userID := event.User().ID
+
+	queued := TRACKS.All()
+	length := len(queued)
+	userTracks := 0
+	identifiers := map[string]bool{}
+
+	for _, track := range queued {
+		identifiers[track.Info.Identifier] = true
+
+		user := UserInfo{}
+		err := track.UserData.Unmarshal(&user)
+		if err != nil {
+			eventLogger(event).Error("Error scanning user", "err", err)
+			continue
+		}
+
+		if user.UserID == userID {
+			userTracks++
+		}
+	}
+
+	accepted := make([]lavalink.Track, 0, len(tracks))
+	refused := []string{}
+
+	for _, track := range tracks {
+		switch {
+		case limits.MaxQueueSize > 0 && length >= limits.MaxQueueSize:
+			refused = append(refused, localize(locale, "queue_full", limits.MaxQueueSize))
+		case limits.BlockStreams && track.Info.IsStream:
+			refused = append(refused, localize(locale, "streams_blocked", trackTitle(track)))
+		case limits.MaxTrackLength > 0 && !track.Info.IsStream && track.Info.Length > lavalink.Duration(limits.MaxTrackLength)*lavalink.Minute:
+			refused = append(refused, localize(locale, "track_too_long", trackTitle(track), limits.MaxTrackLength))
+		case limits.MaxUserTracks > 0 && userTracks >= limits.MaxUserTracks:
+			refused = append(refused, localize(locale, "user_tracks", limits.MaxUserTracks))
+		case limits.RejectDuplicates && identifiers[track.Info.Identifier]:
+			refused = append(refused, localize(locale, "duplicate_track", trackTitle(track)))
+		default:
+			accepted = append(accepted, track)
+			identifiers[track.Info.Identifier] = true
+			length++
+			userTracks++
+		}
+	}
+
+	return accepted, refused
+}
+
+// Added to the reply when some tracks were refused, empty if none were.
+func refusedNote(locale discord.Locale, refused []string) string {
+	if len(refused) == 0 {
+		return ""
+	}
+
+	return localize(locale, "tracks_refused", len(refused), refused[0])
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"path/filepath"
 	"strings"
@@ -20,6 +21,14 @@ var (
 	CHATS = map[snowflake.ID]Chat{}
 
 	LLM_QUEUE = make(chan LLMRequest, 1)
+
+	// Closed when shutting down, new requests are ignored after that
+	LLM_STOP = make(chan struct{})
+	// Closed once the worker stopped
+	LLM_DONE = make(chan struct{})
+
+	// Cancels the request being processed
+	LLM_CONTEXT, LLM_CANCEL = context.WithCancel(context.Background())
 )
 
 type LLMRequest struct {
@@ -58,6 +67,12 @@ type Usage struct {
 }
 
 func handleUserMessage(event *events.MessageCreate) {
+	select {
+	case <-LLM_STOP:
+		return
+	default:
+	}
+
 	messageID := event.MessageID
 	channelID := event.Message.ChannelID
 	content := event.Message.Content
@@ -78,7 +93,7 @@ func handleUserMessage(event *events.MessageCreate) {
 
 	status, err := pingLLMServer()
 	if err != nil || status != 200 {
-		fmt.Printf("Error pinging server: %v | %v\n", status, err)
+		slog.Error("Error pinging server", "status", status, "err", err)
 		addReaction(channelID, messageID, ERR_EMOJI)
 		return
 	}
@@ -91,7 +106,7 @@ func handleUserMessage(event *events.MessageCreate) {
 		url := event.Message.Attachments[0].URL
 		res, err := CLIENT.Rest.HTTPClient().Get(url)
 		if err != nil {
-			fmt.Printf("Error downloading attachment: %v\n", err)
+			messageLogger(event.Message).Error("Error downloading attachment", "err", err)
 			addReaction(channelID, messageID, ERR_EMOJI)
 			return
 		}
@@ -105,7 +120,7 @@ func handleUserMessage(event *events.MessageCreate) {
 		case ".txt":
 			buffer, err := io.ReadAll(res.Body)
 			if err != nil {
-				fmt.Printf("Error reading txt: %v\n", err)
+				messageLogger(event.Message).Error("Error reading txt", "err", err)
 				addReaction(channelID, messageID, ERR_EMOJI)
 				return
 			}
@@ -116,14 +131,14 @@ func handleUserMessage(event *events.MessageCreate) {
 			var buffer bytes.Buffer
 			_, err = io.Copy(&buffer, res.Body)
 			if err != nil {
-				fmt.Printf("Error reading pdf: %v\n", err)
+				messageLogger(event.Message).Error("Error reading pdf", "err", err)
 				addReaction(channelID, messageID, ERR_EMOJI)
 				return
 			}
 
 			body, err = readPDF(buffer)
 			if err != nil {
-				fmt.Printf("Error parsing pdf: %v\n", err)
+				messageLogger(event.Message).Error("Error parsing pdf", "err", err)
 				addReaction(channelID, messageID, ERR_EMOJI)
 				return
 			}
@@ -134,10 +149,17 @@ func handleUserMessage(event *events.MessageCreate) {
 		prompt = event.Message.Content
 	}
 
+	METRICS.LLMQueued(1)
+
+	// The worker stops reading the queue on shutdown
 	go func() {
-		LLM_QUEUE <- LLMRequest{
+		select {
+		case LLM_QUEUE <- LLMRequest{
 			Prompt:  prompt,
 			Message: event.Message,
+		}:
+		case <-LLM_STOP:
+			METRICS.LLMQueued(-1)
 		}
 	}()
 }
@@ -149,7 +171,7 @@ func submitLLMChat(user snowflake.ID, prompt string) (LLMResult, error) {
 			Messages: []Message{
 				{
 					Role:    "system",
-					Content: CONFIG.Prompt,
+					Content: CONFIG.Load().LLM.Prompt,
 				},
 			},
 		}
@@ -170,7 +192,7 @@ func submitLLMChat(user snowflake.ID, prompt string) (LLMResult, error) {
 
 	buffer := bytes.NewBuffer(body)
 
-	req, err := http.NewRequest("POST", "http://localhost:2444/v1/chat/completions", buffer)
+	req, err := http.NewRequestWithContext(LLM_CONTEXT, "POST", CONFIG.Load().LLM.URL+"/v1/chat/completions", buffer)
 	if err != nil {
 		return LLMResult{}, err
 	}
@@ -206,20 +228,41 @@ func submitLLMChat(user snowflake.ID, prompt string) (LLMResult, error) {
 	return result, nil
 }
 
+// Processes the queued requests one at a time until shutdown.
+func llmWorker() {
+	defer close(LLM_DONE)
+
+	for {
+		select {
+		case <-LLM_STOP:
+			return
+		case request := <-LLM_QUEUE:
+			METRICS.LLMQueued(-1)
+			messageLogger(request.Message).Info("Processing LLM request")
+			processLLM(request)
+		}
+	}
+}
+
 func processLLM(request LLMRequest) {
 	err := CLIENT.Rest.SendTyping(request.Message.ChannelID)
 	if err != nil {
-		fmt.Printf("Error sending typing: %v\n", err)
+		messageLogger(request.Message).Error("Error sending typing", "err", err)
 	}
 
+	start := time.Now()
+
 	result, err := submitLLMChat(request.Message.Author.ID, request.Prompt)
 	if err != nil {
-		fmt.Printf("Error submitting chat: %v\n", err)
+		METRICS.LLMDone(time.Since(start), Usage{}, LLM_ERROR)
+		messageLogger(request.Message).Error("Error submitting chat", "err", err)
 		addReaction(request.Message.ChannelID, request.Message.ID, ERR_EMOJI)
 		return
 	}
 
-	fmt.Printf("User %s | Time: %ds | Prompt Tokens: %d | Completion Tokens: %d\n", request.Message.Author.Username, result.Usage.TotalTime, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+	METRICS.LLMDone(time.Since(start), result.Usage, LLM_OK)
+
+	messageLogger(request.Message).Info("LLM request done", "username", request.Message.Author.Username, "seconds", result.Usage.TotalTime, "prompt_tokens", result.Usage.PromptTokens, "completion_tokens", result.Usage.CompletionTokens)
 
 	// If the message is too long, send it as a file
 	if len(result.Content) > 1950 {
@@ -230,7 +273,7 @@ func processLLM(request LLMRequest) {
 
 		_, err = CLIENT.Rest.CreateMessage(request.Message.ChannelID, message.Build())
 		if err != nil {
-			fmt.Printf("Error sending reply with attachment: %v\n", err)
+			messageLogger(request.Message).Error("Error sending reply with attachment", "err", err)
 			addReaction(request.Message.ChannelID, request.Message.ID, ERR_EMOJI)
 			return
 		}
@@ -242,7 +285,7 @@ func processLLM(request LLMRequest) {
 
 	_, err = CLIENT.Rest.CreateMessage(request.Message.ChannelID, message)
 	if err != nil {
-		fmt.Printf("Error sending reply: %v\n", err)
+		messageLogger(request.Message).Error("Error sending reply", "err", err)
 		addReaction(request.Message.ChannelID, request.Message.ID, ERR_EMOJI)
 	}
 }
@@ -251,7 +294,7 @@ func pingLLMServer() (int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", "http://localhost:2444/health", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", CONFIG.Load().LLM.URL+"/health", nil)
 	if err != nil {
 		return 0, err
 	}
@@ -260,6 +303,7 @@ func pingLLMServer() (int, error) {
 	if err != nil {
 		return 0, err
 	}
+	defer res.Body.Close()
 
 	return res.StatusCode, nil
 }
@@ -272,3 +316,25 @@ func reset(event *events.ApplicationCommandInteractionCreate) {
 	message := fmt.Sprintf("Cleared %d messages from your chat.", length)
 	reply(event, message)
 }
+
+var (
+	LLM_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "reset",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "reset",
+					discord.LocalePortugueseBR: "resetar",
+				},
+				Description: "Resets your chat history with the bot",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Resets your chat history with the bot",
+					discord.LocalePortugueseBR: "Apaga seu histórico de conversa com o bot",
+				},
+			},
+			Handler:  reset,
+			Category: CATEGORY_LLM,
+			Feature:  FEATURE_LLM,
+		},
+	}
+)
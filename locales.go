@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// Messages shown to users in their Discord language, falling back to English.
+var MESSAGES = map[string]map[discord.Locale]string{
+	"queue_full": {
+		discord.LocaleEnglishUS:    "The queue is full, it can have at most %d tracks.",
+		discord.LocalePortugueseBR: "A fila está cheia, ela pode ter no máximo %d músicas.",
+	},
+	"track_too_long": {
+		discord.LocaleEnglishUS:    "%s is longer than the %d minute limit.",
+		discord.LocalePortugueseBR: "%s passa do limite de %d minutos.",
+	},
+	"streams_blocked": {
+		discord.LocaleEnglishUS:    "%s is a live stream, streams can't be queued.",
+		discord.LocalePortugueseBR: "%s é uma transmissão ao vivo, transmissões não podem ser adicionadas.",
+	},
+	"user_tracks": {
+		discord.LocaleEnglishUS:    "You already have %d tracks in the queue, which is the limit.",
+		discord.LocalePortugueseBR: "Você já tem %d músicas na fila, que é o limite.",
+	},
+	"duplicate_track": {
+		discord.LocaleEnglishUS:    "%s is already in the queue.",
+		discord.LocalePortugueseBR: "%s já está na fila.",
+	},
+	"command_unknown": {
+		discord.LocaleEnglishUS:    "Unknown command, please use `/help` for a list of commands.",
+		discord.LocalePortugueseBR: "Comando desconhecido, use `/ajuda` para ver a lista de comandos.",
+	},
+	"command_subcommand": {
+		discord.LocaleEnglishUS:    "Please provide a subcommand.",
+		discord.LocalePortugueseBR: "Escolha um subcomando.",
+	},
+	"command_disabled": {
+		discord.LocaleEnglishUS:    "This command is disabled.",
+		discord.LocalePortugueseBR: "Este comando está desativado.",
+	},
+	"command_guild_only": {
+		discord.LocaleEnglishUS:    "This command can only be used in a server.",
+		discord.LocalePortugueseBR: "Este comando só pode ser usado em um servidor.",
+	},
+	"command_permissions": {
+		discord.LocaleEnglishUS:    "You don't have permission to use this command.",
+		discord.LocalePortugueseBR: "Você não tem permissão para usar este comando.",
+	},
+	"command_cooldown": {
+		discord.LocaleEnglishUS:    "Slow down, you can use this command again in %d seconds.",
+		discord.LocalePortugueseBR: "Calma, você pode usar este comando de novo em %d segundos.",
+	},
+	"help_title": {
+		discord.LocaleEnglishUS:    "Commands",
+		discord.LocalePortugueseBR: "Comandos",
+	},
+	"help_description": {
+		discord.LocaleEnglishUS:    "Use `/help command:<name>` to see the options, examples and requirements of a command.",
+		discord.LocalePortugueseBR: "Use `/ajuda comando:<nome>` para ver as opções, exemplos e requisitos de um comando.",
+	},
+	"help_unknown": {
+		discord.LocaleEnglishUS:    "There's no command called `%s`.",
+		discord.LocalePortugueseBR: "Não existe um comando chamado `%s`.",
+	},
+	"help_message_command": {
+		discord.LocaleEnglishUS:    "Message menu command, right click a message and open Apps",
+		discord.LocalePortugueseBR: "Comando do menu de mensagens, clique com o botão direito em uma mensagem e abra Apps",
+	},
+	"help_options": {
+		discord.LocaleEnglishUS:    "Options",
+		discord.LocalePortugueseBR: "Opções",
+	},
+	"help_required": {
+		discord.LocaleEnglishUS:    "(required)",
+		discord.LocalePortugueseBR: "(obrigatório)",
+	},
+	"help_examples": {
+		discord.LocaleEnglishUS:    "Examples",
+		discord.LocalePortugueseBR: "Exemplos",
+	},
+	"help_requirements": {
+		discord.LocaleEnglishUS:    "Requirements",
+		discord.LocalePortugueseBR: "Requisitos",
+	},
+	"help_guild_only": {
+		discord.LocaleEnglishUS:    "Only works in servers",
+		discord.LocalePortugueseBR: "Só funciona em servidores",
+	},
+	"help_permissions": {
+		discord.LocaleEnglishUS:    "Needs the %s permission",
+		discord.LocalePortugueseBR: "Precisa da permissão %s",
+	},
+	"help_cooldown": {
+		discord.LocaleEnglishUS:    "Can be used once every %d seconds",
+		discord.LocalePortugueseBR: "Pode ser usado uma vez a cada %d segundos",
+	},
+	"category_general": {
+		discord.LocaleEnglishUS:    "General",
+		discord.LocalePortugueseBR: "Geral",
+	},
+	"category_llm": {
+		discord.LocaleEnglishUS:    "LLM",
+		discord.LocalePortugueseBR: "LLM",
+	},
+	"category_random": {
+		discord.LocaleEnglishUS:    "Random",
+		discord.LocalePortugueseBR: "Aleatório",
+	},
+	"category_music": {
+		discord.LocaleEnglishUS:    "Music",
+		discord.LocalePortugueseBR: "Música",
+	},
+	"category_admin": {
+		discord.LocaleEnglishUS:    "Admin",
+		discord.LocalePortugueseBR: "Administração",
+	},
+	"tracks_refused": {
+		discord.LocaleEnglishUS:    "%d tracks were not queued: %s",
+		discord.LocalePortugueseBR: "%d músicas não foram adicionadas: %s",
+	},
+	"tracks_none": {
+		discord.LocaleEnglishUS:    "No tracks could be queued.",
+		discord.LocalePortugueseBR: "Nenhuma música pôde ser adicionada.",
+	},
+}
+
+func localize(locale discord.Locale, key string, args ...any) string {
+	messages := MESSAGES[key]
+
+	message, ok := messages[locale]
+	if !ok {
+		message = messages[discord.LocaleEnglishUS]
+	}
+
+	return fmt.Sprintf(message, args...)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	LOG_FORMAT_TEXT = "text"
+	LOG_FORMAT_JSON = "json"
+)
+
+var (
+	// Changed by reloading the config
+	LOG_LEVEL = new(slog.LevelVar)
+)
+
+// Sets the default logger, also used by disgo and disgolink.
+func NewLogger() {
+	config := CONFIG.Load().Logging
+
+	LOG_LEVEL.Set(logLevel(config.Level))
+
+	options := &slog.HandlerOptions{Level: LOG_LEVEL}
+
+	var handler slog.Handler = slog.NewTextHandler(os.Stdout, options)
+	if config.Format == LOG_FORMAT_JSON {
+		handler = slog.NewJSONHandler(os.Stdout, options)
+	}
+
+	slog.SetDefault(slog.New(handler))
+}
+
+// Parses a level name, the config validates it so unknown names fall back to info.
+func logLevel(name string) slog.Level {
+	level := slog.LevelInfo
+	_ = level.UnmarshalText([]byte(name))
+	return level
+}
+
+// Logger with the user, channel, guild and command or component of the interaction.
+func eventLogger(event Interaction) *slog.Logger {
+	deferred, ok := event.(DeferredInteraction)
+	if ok {
+		return eventLogger(deferred.Interaction)
+	}
+
+	logger := slog.With("user_id", event.User().ID, "channel_id", event.Channel().ID())
+
+	if event.GuildID() != nil {
+		logger = logger.With("guild_id", *event.GuildID())
+	}
+
+	switch event := event.(type) {
+	case *events.ApplicationCommandInteractionCreate:
+		logger = logger.With("command", event.Data.CommandName())
+	case *events.ComponentInteractionCreate:
+		logger = logger.With("component", event.Data.CustomID())
+	}
+
+	return logger
+}
+
+func messageLogger(message discord.Message) *slog.Logger {
+	logger := slog.With("message_id", message.ID, "channel_id", message.ChannelID, "user_id", message.Author.ID)
+
+	if message.GuildID != nil {
+		logger = logger.With("guild_id", *message.GuildID)
+	}
+
+	return logger
+}
+
+func guildLogger(guildID snowflake.ID) *slog.Logger {
+	return slog.With("guild_id", guildID)
+}
+
+func trackLogger(guildID snowflake.ID, track lavalink.Track) *slog.Logger {
+	return slog.With("guild_id", guildID, "track", trackTitle(track))
+}
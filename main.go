@@ -2,33 +2,68 @@ package main
 
 import (
 	"flag"
-	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
 )
 
 var (
 	CLIENT = Client{}
-	CONFIG = Config{}
+	CONFIG = atomic.Pointer[Config]{}
 	TRACKS = Tracks{
 		store: make([]lavalink.Track, 0, 10),
 		mu:    sync.Mutex{},
 	}
+	SETTINGS = Settings{
+		store: map[snowflake.ID]GuildSettings{},
+		mu:    sync.Mutex{},
+	}
+	GUILDS = Guilds{
+		store: map[snowflake.ID]GuildState{},
+		mu:    sync.Mutex{},
+	}
+	STATE = State{
+		Sessions: map[string]string{},
+		mu:       sync.Mutex{},
+	}
+	PLAYLISTS = Playlists{
+		store: map[snowflake.ID][]Playlist{},
+		mu:    sync.Mutex{},
+	}
+	COOLDOWNS = Cooldowns{
+		store: map[CooldownKey]time.Time{},
+		mu:    sync.Mutex{},
+	}
+	METRICS = Metrics{
+		commands:    map[CommandOutcome]uint64{},
+		llmRequests: map[string]uint64{},
+		llmLatency: Histogram{
+			Buckets: LLM_LATENCY_BUCKETS,
+			Counts:  make([]uint64, len(LLM_LATENCY_BUCKETS)),
+		},
+		mu: sync.Mutex{},
+	}
 
 	MENTION = ""
 
 	// For LLM
 	HTTP = http.Client{}
 
-	REGISTER = flag.Bool("register", false, "Register commands globally")
+	REGISTER    = flag.Bool("register", false, "Register commands globally, or in the dev guild if one is set")
+	CONFIG_PATH = flag.String("config", "config.json", "Path to the config file")
 
 	ASSETS_PATHS = map[string]string{}
 	ASSETS       = []string{}
+	ASSETS_MU    = sync.Mutex{}
 )
 
 func main() {
@@ -39,22 +74,67 @@ func main() {
 		panic(err)
 	}
 
+	NewLogger()
+	NewCommands()
+
+	err = NewSettings()
+	if err != nil {
+		panic(err)
+	}
+
+	err = NewState()
+	if err != nil {
+		panic(err)
+	}
+
+	err = NewPlaylists()
+	if err != nil {
+		panic(err)
+	}
+
 	err = NewClient()
 	if err != nil {
 		panic(err)
 	}
 
-	go func() {
-		for request := range LLM_QUEUE {
-			fmt.Printf("Processing LLM request, message ID: %s\n", request.Message.ID)
-			processLLM(request)
-		}
-	}()
+	if CONFIG.Load().Features.Music {
+		restoreQueue()
+
+		go watchNodes()
+
+		// Keeps the saved playback position close to the real one
+		go func() {
+			for range time.Tick(STATE_SAVE_INTERVAL) {
+				saveAllStates()
+			}
+		}()
+	}
+
+	go llmWorker()
 
-	fmt.Println("Client is ready")
-	fmt.Println("Press Ctrl+C to exit")
+	NewHTTPServer()
+
+	slog.Info("Client is ready, press Ctrl+C to exit")
 
 	s := make(chan os.Signal, 1)
-	signal.Notify(s, syscall.SIGINT, syscall.SIGTERM)
-	<-s
+	signal.Notify(s, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	// SIGHUP reloads the config, anything else exits
+	for sig := range s {
+		if sig != syscall.SIGHUP {
+			break
+		}
+
+		restart, err := reloadConfig()
+		if err != nil {
+			slog.Error("Error reloading config", "err", err)
+			continue
+		}
+
+		if len(restart) > 0 {
+			slog.Warn("Some changes need a restart", "fields", strings.Join(restart, ", "))
+		}
+	}
+
+	shutdown()
 }
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgolink/v3/disgolink"
+)
+
+const (
+	METRICS_PREFIX = "joel_"
+
+	COMMAND_OK       = "ok"
+	COMMAND_DISABLED = "disabled"
+	COMMAND_UNKNOWN  = "unknown"
+	COMMAND_REFUSED  = "refused"
+	COMMAND_COOLDOWN = "cooldown"
+	COMMAND_PANIC    = "panic"
+
+	LLM_OK    = "ok"
+	LLM_ERROR = "error"
+)
+
+var (
+	// Seconds, a 1B model answers in a few seconds but documents can take minutes
+	LLM_LATENCY_BUCKETS = []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300}
+
+	LABEL_ESCAPER = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+type CommandOutcome struct {
+	Command string
+	Outcome string
+}
+
+type Histogram struct {
+	Buckets []float64
+	// Not cumulative, summed when written
+	Counts []uint64
+	Sum    float64
+	Count  uint64
+}
+
+type Metrics struct {
+	commands         map[CommandOutcome]uint64
+	llmRequests      map[string]uint64
+	llmLatency       Histogram
+	llmQueued        int
+	promptTokens     uint64
+	completionTokens uint64
+	mu               sync.Mutex
+}
+
+func (h *Histogram) Observe(value float64) {
+	for i, bucket := range h.Buckets {
+		if value <= bucket {
+			h.Counts[i]++
+			break
+		}
+	}
+
+	h.Sum += value
+	h.Count++
+}
+
+func (m *Metrics) CommandDone(command string, outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.commands[CommandOutcome{command, outcome}]++
+}
+
+// Deferred by the command listeners, a panicking command is counted before the panic continues.
+func countCommand(command string, outcome *string) {
+	r := recover()
+	if r != nil {
+		*outcome = COMMAND_PANIC
+	}
+
+	METRICS.CommandDone(command, *outcome)
+
+	if r != nil {
+		panic(r)
+	}
+}
+
+// Requests waiting for the worker, including the ones not in LLM_QUEUE yet.
+func (m *Metrics) LLMQueued(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.llmQueued += delta
+}
+
+// Failed requests are timed too, usage is empty for them.
+func (m *Metrics) LLMDone(latency time.Duration, usage Usage, outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.llmRequests[outcome]++
+	m.llmLatency.Observe(latency.Seconds())
+	m.promptTokens += uint64(usage.PromptTokens)
+	m.completionTokens += uint64(usage.CompletionTokens)
+}
+
+// Writes the metrics in the Prometheus text format.
+func (m *Metrics) Write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	writeHeader(w, "commands_total", "counter", "Commands executed by name and outcome")
+
+	keys := slices.SortedFunc(maps.Keys(m.commands), func(a CommandOutcome, b CommandOutcome) int {
+		return strings.Compare(a.Command+" "+a.Outcome, b.Command+" "+b.Outcome)
+	})
+
+	for _, key := range keys {
+		writeSample(w, "commands_total", float64(m.commands[key]), "command", key.Command, "outcome", key.Outcome)
+	}
+
+	writeHeader(w, "llm_queue_depth", "gauge", "LLM requests waiting to be processed")
+	writeSample(w, "llm_queue_depth", float64(m.llmQueued))
+
+	writeHeader(w, "llm_requests_total", "counter", "LLM requests processed by outcome")
+
+	for _, outcome := range slices.Sorted(maps.Keys(m.llmRequests)) {
+		writeSample(w, "llm_requests_total", float64(m.llmRequests[outcome]), "outcome", outcome)
+	}
+
+	writeHeader(w, "llm_request_duration_seconds", "histogram", "Time taken by the LLM server to answer or fail")
+
+	cumulative := uint64(0)
+	for i, bucket := range m.llmLatency.Buckets {
+		cumulative += m.llmLatency.Counts[i]
+		writeSample(w, "llm_request_duration_seconds_bucket", float64(cumulative), "le", strconv.FormatFloat(bucket, 'g', -1, 64))
+	}
+
+	writeSample(w, "llm_request_duration_seconds_bucket", float64(m.llmLatency.Count), "le", "+Inf")
+	writeSample(w, "llm_request_duration_seconds_sum", m.llmLatency.Sum)
+	writeSample(w, "llm_request_duration_seconds_count", float64(m.llmLatency.Count))
+
+	writeHeader(w, "llm_prompt_tokens_total", "counter", "Prompt tokens used by LLM requests")
+	writeSample(w, "llm_prompt_tokens_total", float64(m.promptTokens))
+
+	writeHeader(w, "llm_completion_tokens_total", "counter", "Completion tokens generated by LLM requests")
+	writeSample(w, "llm_completion_tokens_total", float64(m.completionTokens))
+}
+
+func writeHeader(w io.Writer, name string, kind string, help string) {
+	fmt.Fprintf(w, "# HELP %s%s %s\n# TYPE %s%s %s\n", METRICS_PREFIX, name, help, METRICS_PREFIX, name, kind)
+}
+
+// Labels are given as name and value pairs.
+func writeSample(w io.Writer, name string, value float64, labels ...string) {
+	pairs := []string{}
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], LABEL_ESCAPER.Replace(labels[i+1])))
+	}
+
+	set := ""
+	if len(pairs) > 0 {
+		set = "{" + strings.Join(pairs, ",") + "}"
+	}
+
+	fmt.Fprintf(w, "%s%s%s %s\n", METRICS_PREFIX, name, set, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// Metrics read from the clients when scraped.
+func writeClientMetrics(w io.Writer) {
+	if CLIENT.Bot.HasGateway() {
+		writeHeader(w, "gateway_latency_seconds", "gauge", "Discord gateway heartbeat latency")
+		writeSample(w, "gateway_latency_seconds", CLIENT.Bot.Gateway().Latency().Seconds())
+	}
+
+	if CLIENT.Lavalink == nil {
+		return
+	}
+
+	writeHeader(w, "queue_tracks", "gauge", "Tracks in the queue, including the one playing")
+	writeSample(w, "queue_tracks", float64(TRACKS.Len()))
+
+	players := 0
+	playing := 0
+	CLIENT.Lavalink.ForPlayers(func(player disgolink.Player) {
+		players++
+		if player.Track() != nil && !player.Paused() {
+			playing++
+		}
+	})
+
+	writeHeader(w, "players", "gauge", "Lavalink players created by the bot")
+	writeSample(w, "players", float64(players))
+
+	writeHeader(w, "players_playing", "gauge", "Lavalink players playing a track")
+	writeSample(w, "players_playing", float64(playing))
+
+	nodes := []disgolink.Node{}
+	CLIENT.Lavalink.ForNodes(func(node disgolink.Node) {
+		nodes = append(nodes, node)
+	})
+
+	slices.SortFunc(nodes, func(a disgolink.Node, b disgolink.Node) int {
+		return strings.Compare(a.Config().Name, b.Config().Name)
+	})
+
+	gauges := []struct {
+		name  string
+		help  string
+		value func(node disgolink.Node) float64
+	}{
+		{"node_up", "Whether the Lavalink node is connected", func(node disgolink.Node) float64 {
+			if node.Status() == disgolink.StatusConnected {
+				return 1
+			}
+			return 0
+		}},
+		{"node_players", "Players on the Lavalink node", func(node disgolink.Node) float64 {
+			return float64(node.Stats().Players)
+		}},
+		{"node_playing_players", "Players playing on the Lavalink node", func(node disgolink.Node) float64 {
+			return float64(node.Stats().PlayingPlayers)
+		}},
+		{"node_system_load", "System CPU load of the Lavalink node", func(node disgolink.Node) float64 {
+			return node.Stats().CPU.SystemLoad
+		}},
+		{"node_lavalink_load", "Lavalink CPU load of the Lavalink node", func(node disgolink.Node) float64 {
+			return node.Stats().CPU.LavalinkLoad
+		}},
+		{"node_memory_used_bytes", "Memory used by the Lavalink node", func(node disgolink.Node) float64 {
+			return float64(node.Stats().Memory.Used)
+		}},
+		{"node_uptime_seconds", "Uptime of the Lavalink node", func(node disgolink.Node) float64 {
+			return float64(node.Stats().Uptime) / 1000
+		}},
+		{"node_frame_deficit", "Audio frames missing in the last minute on the Lavalink node", func(node disgolink.Node) float64 {
+			frames := node.Stats().FrameStats
+			if frames == nil {
+				return 0
+			}
+			return float64(frames.Deficit)
+		}},
+	}
+
+	for _, gauge := range gauges {
+		writeHeader(w, gauge.name, "gauge", gauge.help)
+		for _, node := range nodes {
+			writeSample(w, gauge.name, gauge.value(node), "node", node.Config().Name)
+		}
+	}
+}
+
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	METRICS.Write(w)
+	writeClientMetrics(w)
+}
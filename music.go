@@ -4,76 +4,123 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
 )
 
 var (
 	ErrNoTracksFound = errors.New("no tracks found")
 	ErrLoadingTracks = errors.New("loading tracks failed")
-)
 
-func play(event *events.ApplicationCommandInteractionCreate, url string) {
-	url = strings.TrimSpace(url)
-	if !strings.HasPrefix(url, "http") {
-		url = "ytsearch:" + url
-	}
+	ErrInvalidPosition = errors.New("invalid position, use formats like `1:23`, `83`, `+30s` or `-10s`")
+)
 
-	guildID := *event.GuildID()
+func playCommand(event *events.ApplicationCommandInteractionCreate) {
+	data := event.SlashCommandInteractionData()
 
-	avatar := ""
-	if event.User().AvatarURL() != nil {
-		avatar = *event.User().AvatarURL()
+	file, ok := data.OptAttachment("file")
+	if ok {
+		playAttachment(event, file)
+		return
 	}
 
-	user := UserInfo{
-		Username: event.User().EffectiveName(),
-		Avatar:   avatar,
+	query, ok := data.OptString("query")
+	if !ok {
+		reply(event, "Please provide a query or a file.")
+		return
 	}
 
-	track, err := handleUserQuery(user, url)
+	source, _ := data.OptString("source")
+
+	play(event, query, source)
+}
+
+func play(event *events.ApplicationCommandInteractionCreate, query string, source string) {
+	track, err := loadQuery(*event.GuildID(), newUserInfo(event), query, source)
 	if err != nil {
 		reply(event, err.Error())
 		return
 	}
 
+	enqueue(event, track)
+}
+
+// Joins the user's voice channel and adds the tracks to the queue, starting playback if nothing is playing.
+func enqueue(event Interaction, tracks ...lavalink.Track) {
+	guildID := *event.GuildID()
+
 	userVoice, err := CLIENT.Rest.GetUserVoiceState(guildID, event.User().ID)
 	if userVoice == nil || err != nil {
 		reply(event, "You are not in a voice channel.")
 		return
 	}
 
+	tracks, refused := checkLimits(event, tracks)
+	if len(tracks) == 0 {
+		if len(refused) > 0 {
+			replyEphemeral(event, refused[0])
+		} else {
+			replyEphemeral(event, localize(event.Locale(), "tracks_none"))
+		}
+		return
+	}
+
+	note := refusedNote(event.Locale(), refused)
+
 	updateVoiceChannel(event, userVoice.ChannelID)
 
+	GUILDS.Update(guildID, func(state *GuildState) {
+		state.TextChannelID = event.Channel().ID()
+	})
+
 	player := CLIENT.Lavalink.Player(guildID)
 
 	if !TRACKS.Empty() {
-		TRACKS.Push(track)
-		fmt.Printf("Queued track: %s\n", track.Info.Title)
-		reply(event, fmt.Sprintf("Queued track: %s\n", track.Info.Title))
+		for _, track := range tracks {
+			TRACKS.Push(track)
+		}
+		saveState(guildID)
+
+		if len(tracks) == 1 {
+			eventLogger(event).Info("Queued track", "track", trackTitle(tracks[0]))
+			reply(event, strings.TrimSpace(fmt.Sprintf("Queued track: %s\n%s", trackTitle(tracks[0]), note)))
+			return
+		}
+
+		eventLogger(event).Info("Queued tracks", "tracks", len(tracks))
+		reply(event, strings.TrimSpace(fmt.Sprintf("Queued %d tracks.\n%s", len(tracks), note)))
 		return
 	}
 
-	TRACKS.Push(track)
+	for _, track := range tracks {
+		TRACKS.Push(track)
+	}
 
-	err = player.Update(context.TODO(), lavalink.WithTrack(track))
+	volume := SETTINGS.Get(guildID).Volume
+	_, filters := guildFilters(guildID)
+
+	err = player.Update(context.TODO(), lavalink.WithTrack(tracks[0]), lavalink.WithVolume(volume), lavalink.WithFilters(filters))
 	if err != nil {
-		fmt.Printf("Error playing track: %v\n", err)
+		eventLogger(event).Error("Error playing track", "err", err)
 		reply(event, err.Error())
 		return
 	}
 
-	embed := TRACKS.GetTrackEmbed(track)
-	message := discord.NewMessageCreateBuilder().SetEmbeds(embed).Build()
-	sendMessage(event, message)
+	sendNowPlaying(event, note)
 }
 
-func pause(event *events.ApplicationCommandInteractionCreate) {
+func pause(event Interaction) {
 	guildID := *event.GuildID()
 
+	if !requireSameChannel(event) {
+		return
+	}
+
 	voice := getBotVoiceState(event)
 	if voice == nil {
 		reply(event, "The bot is not in a voice channel.")
@@ -89,14 +136,21 @@ func pause(event *events.ApplicationCommandInteractionCreate) {
 
 	err := player.Update(context.TODO(), lavalink.WithPaused(true))
 	if err != nil {
-		fmt.Printf("Error pausing: %v\n", err)
+		eventLogger(event).Error("Error pausing", "err", err)
 		reply(event, err.Error())
+		return
 	}
+
+	reply(event, "Paused.")
 }
 
-func resume(event *events.ApplicationCommandInteractionCreate) {
+func resume(event Interaction) {
 	guildID := *event.GuildID()
 
+	if !requireSameChannel(event) {
+		return
+	}
+
 	voice := getBotVoiceState(event)
 	if voice == nil {
 		reply(event, "The bot is not in a voice channel.")
@@ -112,68 +166,97 @@ func resume(event *events.ApplicationCommandInteractionCreate) {
 
 	err := player.Update(context.TODO(), lavalink.WithPaused(false))
 	if err != nil {
-		fmt.Printf("Error resuming: %v\n", err)
+		eventLogger(event).Error("Error resuming", "err", err)
 		reply(event, err.Error())
+		return
 	}
+
+	reply(event, "Resumed.")
 }
 
-func skip(event *events.ApplicationCommandInteractionCreate) {
+func skip(event Interaction) {
 	guildID := *event.GuildID()
 
+	if !requireSameChannel(event) {
+		return
+	}
+
 	voice := getBotVoiceState(event)
 	if voice == nil {
 		reply(event, "The bot is not in a voice channel.")
 		return
 	}
 
-	length := TRACKS.Len()
-
-	if length == 0 {
+	if TRACKS.Empty() {
 		reply(event, "No tracks currently playing.")
 		return
 	}
 
-	player := CLIENT.Lavalink.Player(guildID)
-
-	if length == 1 {
-		err := player.Update(context.TODO(), lavalink.WithNullTrack())
-		if err != nil {
-			fmt.Printf("Error playing track: %v\n", err)
-			reply(event, err.Error())
+	if CONFIG.Load().Music.VoteSkipPercentage > 0 && !canBypassVotes(event) {
+		votes, required, added := voteSkip(guildID, event.User().ID)
+		if !added {
+			replyEphemeral(event, fmt.Sprintf("You already voted to skip (%d/%d).", votes, required))
 			return
 		}
 
-		return
-	}
-
-	user := UserInfo{
-		Skipped: true,
+		if votes < required {
+			reply(event, fmt.Sprintf("Voted to skip %s (%d/%d).", trackTitle(TRACKS.First()), votes, required))
+			refreshNowPlaying(guildID)
+			return
+		}
 	}
 
-	skippedTrack, err := TRACKS.First().WithUserData(user)
-	if err != nil {
-		fmt.Printf("Error adding skipped user data: %v\n", err)
-		return
-	}
+	player := CLIENT.Lavalink.Player(guildID)
 
-	TRACKS.Replace(0, skippedTrack)
-	track := TRACKS.Get(1)
+	title := trackTitle(TRACKS.First())
 
-	err = player.Update(context.TODO(), lavalink.WithNullTrack())
+	// Ending the track moves the queue forward in onTrackEnd
+	err := player.Update(context.TODO(), lavalink.WithNullTrack())
 	if err != nil {
-		fmt.Printf("Error playing track: %v\n", err)
+		eventLogger(event).Error("Error playing track", "err", err)
 		reply(event, err.Error())
 		return
 	}
 
-	embed := TRACKS.GetTrackEmbed(track)
-	message := discord.NewMessageCreateBuilder().SetEmbeds(embed).Build()
-	sendMessage(event, message)
+	reply(event, fmt.Sprintf("Skipped %s.", title))
 }
 
-func stop(event *events.ApplicationCommandInteractionCreate) {
+// Adds the user's vote to skip the current track, returning the votes of users still listening,
+// how many are needed and if the vote was new.
+func voteSkip(guildID snowflake.ID, userID snowflake.ID) (int, int, bool) {
+	listeners := voiceListeners(guildID)
+	required := requiredSkipVotes(len(listeners))
+
+	added := false
+	votes := 0
+
+	GUILDS.Update(guildID, func(state *GuildState) {
+		if !slices.Contains(state.SkipVotes, userID) {
+			state.SkipVotes = append(state.SkipVotes, userID)
+			added = true
+		}
+
+		for _, voter := range state.SkipVotes {
+			if slices.Contains(listeners, voter) {
+				votes++
+			}
+		}
+	})
+
+	return votes, required, added
+}
+
+func requiredSkipVotes(listeners int) int {
+	return max(1, (listeners*CONFIG.Load().Music.VoteSkipPercentage+99)/100)
+}
+
+func stop(event Interaction) {
 	guildID := *event.GuildID()
 
+	if !requireSameChannel(event) {
+		return
+	}
+
 	voice := getBotVoiceState(event)
 	if voice == nil {
 		reply(event, "The bot is not in a voice channel.")
@@ -187,11 +270,17 @@ func stop(event *events.ApplicationCommandInteractionCreate) {
 
 	player := CLIENT.Lavalink.Player(guildID)
 
+	// Clearing first so onTrackEnd doesn't start the next track
+	TRACKS.Clear()
+
 	err := player.Update(context.TODO(), lavalink.WithNullTrack())
 	if err != nil {
-		fmt.Printf("Error stopping: %v\n", err)
+		eventLogger(event).Error("Error stopping", "err", err)
 		reply(event, err.Error())
+		return
 	}
+
+	reply(event, "Stopped.")
 }
 
 func join(event *events.ApplicationCommandInteractionCreate) {
@@ -199,7 +288,7 @@ func join(event *events.ApplicationCommandInteractionCreate) {
 
 	voice, err := CLIENT.Rest.GetCurrentUserVoiceState(guildID)
 	if err != nil {
-		fmt.Printf("Error getting bot voice state: %v\n", err)
+		eventLogger(event).Error("Error getting bot voice state", "err", err)
 		reply(event, err.Error())
 		return
 	}
@@ -220,9 +309,13 @@ func join(event *events.ApplicationCommandInteractionCreate) {
 	updateVoiceChannel(event, userVoice.ChannelID)
 }
 
-func leave(event *events.ApplicationCommandInteractionCreate) {
+func leave(event Interaction) {
 	guildID := *event.GuildID()
 
+	if !requireSameChannel(event) {
+		return
+	}
+
 	voice := getBotVoiceState(event)
 	if voice == nil {
 		reply(event, "The bot is not in a voice channel.")
@@ -232,9 +325,12 @@ func leave(event *events.ApplicationCommandInteractionCreate) {
 	if !TRACKS.Empty() {
 		player := CLIENT.Lavalink.Player(guildID)
 
+		// Clearing first so onTrackEnd doesn't start the next track
+		TRACKS.Clear()
+
 		err := player.Update(context.TODO(), lavalink.WithNullTrack())
 		if err != nil {
-			fmt.Printf("Error stopping track before leaving: %v\n", err)
+			eventLogger(event).Error("Error stopping track before leaving", "err", err)
 			reply(event, err.Error())
 			return
 		}
@@ -243,37 +339,514 @@ func leave(event *events.ApplicationCommandInteractionCreate) {
 	updateVoiceChannel(event, nil)
 }
 
-func queue(event *events.ApplicationCommandInteractionCreate) {
+func queue(event Interaction) {
 	if TRACKS.Empty() {
 		reply(event, "No tracks currently playing.")
 		return
 	}
 
-	tracks := TRACKS.Few(5)
+	player := CLIENT.Lavalink.Player(*event.GuildID())
+
+	embed, pages := TRACKS.GetQueueEmbed(0, player.Position())
+	message := discord.NewMessageCreateBuilder().
+		SetEmbeds(embed).
+		AddActionRow(queueButtons(0, pages)...).
+		Build()
+
+	sendMessage(event, message)
+}
+
+func queuePage(event *events.ComponentInteractionCreate, page int) {
+	if TRACKS.Empty() {
+		message := discord.NewMessageUpdateBuilder().
+			SetContent("No tracks currently playing.").
+			ClearEmbeds().
+			ClearContainerComponents().
+			Build()
 
-	var queue []string
-	for i, track := range tracks {
-		user := UserInfo{}
-		err := track.UserData.Unmarshal(&user)
+		err := event.UpdateMessage(message)
 		if err != nil {
-			fmt.Printf("Error scanning user: %v\n", err)
-			reply(event, err.Error())
-			return
+			eventLogger(event).Error("Error updating queue", "err", err)
 		}
+		return
+	}
+
+	player := CLIENT.Lavalink.Player(*event.GuildID())
+
+	embed, pages := TRACKS.GetQueueEmbed(page, player.Position())
+	page = max(0, min(page, pages-1))
 
-		queue = append(queue, fmt.Sprintf("%d. %s - %s", i+1, track.Info.Title, user.Username))
+	message := discord.NewMessageUpdateBuilder().
+		SetEmbeds(embed).
+		SetContainerComponents(discord.NewActionRow(queueButtons(page, pages)...)).
+		Build()
+
+	err := event.UpdateMessage(message)
+	if err != nil {
+		eventLogger(event).Error("Error updating queue", "err", err)
 	}
+}
+
+func queueButtons(page int, pages int) []discord.InteractiveComponent {
+	previous := discord.NewSecondaryButton("Previous", fmt.Sprintf("queue:%d", page-1)).WithDisabled(page <= 0)
+	next := discord.NewSecondaryButton("Next", fmt.Sprintf("queue:%d", page+1)).WithDisabled(page >= pages-1)
+
+	return []discord.InteractiveComponent{previous, next}
+}
+
+func playing(event Interaction) {
+	sendNowPlaying(event, "")
+}
 
-	reply(event, strings.Join(queue, "\n"))
+func seekCommand(event *events.ApplicationCommandInteractionCreate) {
+	position, ok := event.SlashCommandInteractionData().OptString("position")
+	if !ok {
+		reply(event, "Please provide a position.")
+		return
+	}
+
+	seek(event, position)
 }
 
-func playing(event *events.ApplicationCommandInteractionCreate) {
+func seek(event *events.ApplicationCommandInteractionCreate, input string) {
+	guildID := *event.GuildID()
+
+	if !requireSameChannel(event) {
+		return
+	}
+
+	voice := getBotVoiceState(event)
+	if voice == nil {
+		reply(event, "The bot is not in a voice channel.")
+		return
+	}
+
 	if TRACKS.Empty() {
 		reply(event, "No tracks currently playing.")
 		return
 	}
 
-	embed := TRACKS.GetTrackEmbed(TRACKS.First())
-	message := discord.NewMessageCreateBuilder().SetEmbeds(embed).Build()
-	sendMessage(event, message)
+	track := TRACKS.First()
+	// disgolink does not expose Lavalink's isSeekable, streams are the only tracks that can't be seeked
+	if track.Info.IsStream {
+		reply(event, "The current track is not seekable.")
+		return
+	}
+
+	player := CLIENT.Lavalink.Player(guildID)
+
+	position, err := parseSeekPosition(input, player.Position())
+	if err != nil {
+		reply(event, err.Error())
+		return
+	}
+
+	if position > track.Info.Length {
+		reply(event, fmt.Sprintf("Position is past the end of the track (%s).", formatDuration(track.Info.Length)))
+		return
+	}
+
+	err = player.Update(context.TODO(), lavalink.WithPosition(position))
+	if err != nil {
+		eventLogger(event).Error("Error seeking", "err", err)
+		reply(event, err.Error())
+		return
+	}
+
+	reply(event, fmt.Sprintf("Seeked to %s.", formatDuration(position)))
 }
+
+func volumeCommand(event *events.ApplicationCommandInteractionCreate) {
+	level, ok := event.SlashCommandInteractionData().OptInt("volume")
+	if !ok {
+		reply(event, "Please provide a volume.")
+		return
+	}
+
+	volume(event, level)
+}
+
+func volume(event *events.ApplicationCommandInteractionCreate, volume int) {
+	guildID := *event.GuildID()
+
+	if !requireSameChannel(event) {
+		return
+	}
+
+	if volume < MIN_VOLUME || volume > MAX_VOLUME {
+		reply(event, "Volume must be between 0 and 200.")
+		return
+	}
+
+	err := SETTINGS.Update(guildID, func(settings *GuildSettings) {
+		settings.Volume = volume
+	})
+	if err != nil {
+		eventLogger(event).Error("Error saving settings", "err", err)
+		reply(event, err.Error())
+		return
+	}
+
+	if !TRACKS.Empty() {
+		player := CLIENT.Lavalink.Player(guildID)
+
+		err = player.Update(context.TODO(), lavalink.WithVolume(volume))
+		if err != nil {
+			eventLogger(event).Error("Error setting volume", "err", err)
+			reply(event, err.Error())
+			return
+		}
+	}
+
+	reply(event, fmt.Sprintf("Volume set to %d%%.", volume))
+}
+
+var (
+	MUSIC_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name:        "play",
+				Description: "Plays a track",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Plays a track",
+					discord.LocalePortugueseBR: "Toca uma música",
+				},
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name: "query",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "query",
+							discord.LocalePortugueseBR: "pesquisa",
+						},
+						Description: "Can be an URL or a search query",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Can be an URL or a search query",
+							discord.LocalePortugueseBR: "Pode ser uma URL ou uma pesquisa",
+						},
+					},
+					discord.ApplicationCommandOptionAttachment{
+						Name: "file",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "file",
+							discord.LocalePortugueseBR: "arquivo",
+						},
+						Description: "An audio file like mp3, ogg or flac",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "An audio file like mp3, ogg or flac",
+							discord.LocalePortugueseBR: "Um arquivo de áudio como mp3, ogg ou flac",
+						},
+					},
+					discord.ApplicationCommandOptionString{
+						Name: "source",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "source",
+							discord.LocalePortugueseBR: "fonte",
+						},
+						Description: "Where to search first, defaults to the server's source",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Where to search first, defaults to the server's source",
+							discord.LocalePortugueseBR: "Onde pesquisar primeiro, padrão é a fonte do servidor",
+						},
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "YouTube", Value: SOURCE_YOUTUBE},
+							{Name: "YouTube Music", Value: SOURCE_YOUTUBE_MUSIC},
+							{Name: "SoundCloud", Value: SOURCE_SOUNDCLOUD},
+							{Name: "Bandcamp", Value: SOURCE_BANDCAMP},
+						},
+					},
+				},
+			},
+			Handler:   playCommand,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+			Examples:  []string{"/play query:never gonna give you up", "/play query:https://youtu.be/dQw4w9WgXcQ", "/play query:lofi source:soundcloud", "/play file:song.mp3"},
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "stop",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "stop",
+					discord.LocalePortugueseBR: "parar",
+				},
+				Description: "Stops the current track",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Stops the current track",
+					discord.LocalePortugueseBR: "Para a música atual",
+				},
+			},
+			Handler:   interactionHandler(stop),
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "pause",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "pause",
+					discord.LocalePortugueseBR: "pausar",
+				},
+				Description: "Pauses the current track",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Pauses the current track",
+					discord.LocalePortugueseBR: "Pausa a música atual",
+				},
+			},
+			Handler:   interactionHandler(pause),
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "resume",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "resume",
+					discord.LocalePortugueseBR: "resumir",
+				},
+				Description: "Resumes the current track",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Resumes the current track",
+					discord.LocalePortugueseBR: "Resume a música atual",
+				},
+			},
+			Handler:   interactionHandler(resume),
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "skip",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "skip",
+					discord.LocalePortugueseBR: "pular",
+				},
+				Description: "Skips the current track",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Skips the current track",
+					discord.LocalePortugueseBR: "Pula a música atual",
+				},
+			},
+			Handler:   interactionHandler(skip),
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "join",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "join",
+					discord.LocalePortugueseBR: "entrar",
+				},
+				Description: "Joins the voice channel",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Joins the voice channel",
+					discord.LocalePortugueseBR: "Entra no canal de voz",
+				},
+			},
+			Handler:   join,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "leave",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "leave",
+					discord.LocalePortugueseBR: "sair",
+				},
+				Description: "Leaves the voice channel",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Leaves the voice channel",
+					discord.LocalePortugueseBR: "Sai do canal de voz",
+				},
+			},
+			Handler:   interactionHandler(leave),
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "queue",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "queue",
+					discord.LocalePortugueseBR: "fila",
+				},
+				Description: "Displays, exports or imports the queue",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Displays, exports or imports the queue",
+					discord.LocalePortugueseBR: "Exibe, exporta ou importa a fila de músicas",
+				},
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionSubCommand{
+						Name: "show",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "show",
+							discord.LocalePortugueseBR: "mostrar",
+						},
+						Description: "Displays the music queue",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Displays the music queue",
+							discord.LocalePortugueseBR: "Exibe a fila de músicas",
+						},
+					},
+					discord.ApplicationCommandOptionSubCommand{
+						Name: "export",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "export",
+							discord.LocalePortugueseBR: "exportar",
+						},
+						Description: "Sends the queue as M3U and JSON files",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Sends the queue as M3U and JSON files",
+							discord.LocalePortugueseBR: "Envia a fila como arquivos M3U e JSON",
+						},
+					},
+					discord.ApplicationCommandOptionSubCommand{
+						Name: "import",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "import",
+							discord.LocalePortugueseBR: "importar",
+						},
+						Description: "Queues the tracks of a M3U or JSON file",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Queues the tracks of a M3U or JSON file",
+							discord.LocalePortugueseBR: "Adiciona à fila as músicas de um arquivo M3U ou JSON",
+						},
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionAttachment{
+								Name: "file",
+								NameLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "file",
+									discord.LocalePortugueseBR: "arquivo",
+								},
+								Description: "A queue exported with /queue export, or any M3U playlist",
+								DescriptionLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "A queue exported with /queue export, or any M3U playlist",
+									discord.LocalePortugueseBR: "Uma fila exportada com /queue export, ou qualquer playlist M3U",
+								},
+								Required: true,
+							},
+						},
+					},
+				},
+			},
+			Subcommands: map[string]CommandHandler{
+				"show":   interactionHandler(queue),
+				"export": exportQueue,
+				"import": importQueueCommand,
+			},
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+			Examples:  []string{"/queue show", "/queue export", "/queue import file:queue.json"},
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "playing",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "playing",
+					discord.LocalePortugueseBR: "tocando",
+				},
+				Description: "Displays the currently playing track",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Displays the currently playing track",
+					discord.LocalePortugueseBR: "Exibe a música atual",
+				},
+			},
+			Handler:   interactionHandler(playing),
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "seek",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "seek",
+					discord.LocalePortugueseBR: "avancar",
+				},
+				Description: "Seeks to a position in the current track",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Seeks to a position in the current track",
+					discord.LocalePortugueseBR: "Avança para uma posição na música atual",
+				},
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name: "position",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "position",
+							discord.LocalePortugueseBR: "posicao",
+						},
+						Description: "Position like 1:23, 83, +30s or -10s",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Position like 1:23, 83, +30s or -10s",
+							discord.LocalePortugueseBR: "Posição como 1:23, 83, +30s ou -10s",
+						},
+						Required: true,
+					},
+				},
+			},
+			Handler:   seekCommand,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+			Examples:  []string{"/seek position:1:23", "/seek position:+30s", "/seek position:-10s"},
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "volume",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "volume",
+					discord.LocalePortugueseBR: "volume",
+				},
+				Description: "Sets the player volume",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Sets the player volume",
+					discord.LocalePortugueseBR: "Define o volume do player",
+				},
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionInt{
+						Name: "volume",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "volume",
+							discord.LocalePortugueseBR: "volume",
+						},
+						Description: "Volume from 0 to 200",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Volume from 0 to 200",
+							discord.LocalePortugueseBR: "Volume de 0 a 200",
+						},
+						MinValue: &MIN_VOLUME,
+						MaxValue: &MAX_VOLUME,
+						Required: true,
+					},
+				},
+			},
+			Handler:   volumeCommand,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+			Examples:  []string{"/volume volume:50"},
+		},
+		{
+			Create: discord.SlashCommandCreate{
+				Name:        "247",
+				Description: "Toggles staying in the voice channel when idle or alone",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Toggles staying in the voice channel when idle or alone",
+					discord.LocalePortugueseBR: "Alterna ficar no canal de voz quando ocioso ou sozinho",
+				},
+			},
+			Handler:   alwaysOn,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+		},
+	}
+)
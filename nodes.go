@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/disgolink"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	NODE_CHECK_INTERVAL = 10 * time.Second
+)
+
+var (
+	ErrNoNodes = errors.New("could not connect to any Lavalink node")
+)
+
+// Adds every configured node, nodes that fail to connect are skipped as long as one succeeds.
+// watchNodes keeps retrying the skipped ones.
+func connectNodes(client disgolink.Client) error {
+	connected := 0
+
+	for _, config := range CONFIG.Load().Lavalink.Nodes {
+		err := addNode(client, config)
+		if err != nil {
+			slog.Error("Error connecting to node", "node", config.Name, "err", err)
+			continue
+		}
+
+		connected++
+	}
+
+	if connected == 0 {
+		return ErrNoNodes
+	}
+
+	return nil
+}
+
+func addNode(client disgolink.Client, config LavalinkNode) error {
+	slog.Info("Connecting to Lavalink node", "node", config.Name)
+
+	node, err := client.AddNode(context.TODO(), disgolink.NodeConfig{
+		Name:      config.Name,
+		Address:   config.Address,
+		Password:  config.Password,
+		Secure:    config.Secure,
+		SessionID: STATE.Sessions[config.Name],
+	})
+	if err != nil {
+		return err
+	}
+
+	err = enableSessionResuming(node)
+	if err != nil {
+		slog.Error("Error enabling session resuming", "node", config.Name, "err", err)
+	}
+
+	return nil
+}
+
+// Adds the configured nodes that could not connect before.
+func retryNodes() {
+	for _, config := range CONFIG.Load().Lavalink.Nodes {
+		if CLIENT.Lavalink.Node(config.Name) != nil {
+			continue
+		}
+
+		err := addNode(CLIENT.Lavalink, config)
+		if err != nil {
+			slog.Debug("Node is still unreachable", "node", config.Name, "err", err)
+		}
+	}
+}
+
+// Returns the best connected node, preferring the ones in the same region as the guild's voice server.
+// Returns nil if no node is connected.
+func healthyNode(guildID snowflake.ID) disgolink.Node {
+	endpoint := GUILDS.Get(guildID).VoiceEndpoint
+
+	var best disgolink.Node
+	bestInRegion := false
+
+	CLIENT.Lavalink.ForNodes(func(node disgolink.Node) {
+		if node.Status() != disgolink.StatusConnected {
+			return
+		}
+
+		region := nodeRegion(node.Config().Name)
+		inRegion := region != "" && strings.HasPrefix(endpoint, region)
+
+		if best == nil || (inRegion && !bestInRegion) || (inRegion == bestInRegion && lessLoaded(node.Stats(), best.Stats())) {
+			best = node
+			bestInRegion = inRegion
+		}
+	})
+
+	return best
+}
+
+// Whether a has fewer players per core than b, or the same and a lower CPU load.
+// Used instead of disgolink's Stats.Better, which prefers the busier node.
+func lessLoaded(a lavalink.Stats, b lavalink.Stats) bool {
+	aPlayers := float64(a.Players) / float64(max(a.CPU.Cores, 1))
+	bPlayers := float64(b.Players) / float64(max(b.CPU.Cores, 1))
+
+	if aPlayers != bPlayers {
+		return aPlayers < bPlayers
+	}
+
+	return a.CPU.SystemLoad < b.CPU.SystemLoad
+}
+
+// Returns the least loaded connected node, used for loading tracks. Nil if no node is connected.
+func loadingNode() disgolink.Node {
+	var best disgolink.Node
+
+	CLIENT.Lavalink.ForNodes(func(node disgolink.Node) {
+		if node.Status() != disgolink.StatusConnected {
+			return
+		}
+
+		if best == nil || lessLoaded(node.Stats(), best.Stats()) {
+			best = node
+		}
+	})
+
+	return best
+}
+
+func nodeRegion(name string) string {
+	for _, config := range CONFIG.Load().Lavalink.Nodes {
+		if config.Name == name {
+			return config.Region
+		}
+	}
+
+	return ""
+}
+
+// Periodically retries the nodes that never connected and moves players off nodes that went down.
+func watchNodes() {
+	for range time.Tick(NODE_CHECK_INTERVAL) {
+		retryNodes()
+
+		stranded := []snowflake.ID{}
+
+		CLIENT.Lavalink.ForPlayers(func(player disgolink.Player) {
+			if player.Node() == nil || player.Node().Status() != disgolink.StatusConnected {
+				stranded = append(stranded, player.GuildID())
+			}
+		})
+
+		for _, guildID := range stranded {
+			node := healthyNode(guildID)
+			if node == nil {
+				slog.Warn("No healthy Lavalink node to move players to")
+				break
+			}
+
+			migratePlayer(guildID, node)
+		}
+	}
+}
+
+// Recreates the guild's player on another node, continuing the current track where it was.
+func migratePlayer(guildID snowflake.ID, node disgolink.Node) {
+	old := CLIENT.Lavalink.ExistingPlayer(guildID)
+	if old == nil {
+		return
+	}
+
+	guildLogger(guildID).Info("Moving player", "node", node.Config().Name)
+
+	channelID := old.ChannelID()
+	position := old.Position()
+	paused := old.Paused()
+	volume := old.Volume()
+	filters := old.Filters()
+
+	CLIENT.Lavalink.RemovePlayer(guildID)
+	player := CLIENT.Lavalink.PlayerOnNode(node, guildID)
+
+	if channelID == nil {
+		return
+	}
+
+	state := GUILDS.Get(guildID)
+	player.OnVoiceStateUpdate(context.TODO(), channelID, state.VoiceSessionID)
+
+	opts := []lavalink.PlayerUpdateOpt{
+		lavalink.WithVoice(lavalink.VoiceState{
+			Token:     state.VoiceToken,
+			Endpoint:  state.VoiceEndpoint,
+			SessionID: state.VoiceSessionID,
+		}),
+		lavalink.WithVolume(volume),
+		lavalink.WithFilters(filters),
+		lavalink.WithPaused(paused),
+	}
+
+	if !TRACKS.Empty() {
+		opts = append(opts, lavalink.WithTrack(TRACKS.First()), lavalink.WithPosition(position))
+	}
+
+	err := player.Update(context.TODO(), opts...)
+	if err != nil {
+		guildLogger(guildID).Error("Error moving player", "err", err)
+	}
+}
+
+func adminNodes(event *events.ApplicationCommandInteractionCreate) {
+	// The Administrator permission is only checked in servers
+	if event.Member() == nil && !isOwner(event) {
+		replyEphemeral(event, "Only the bot owner can see the nodes outside a server.")
+		return
+	}
+
+	if !CONFIG.Load().Features.Music {
+		replyEphemeral(event, localize(event.Locale(), "command_disabled"))
+		return
+	}
+
+	nodesStatus(event)
+}
+
+func nodesStatus(event *events.ApplicationCommandInteractionCreate) {
+	embed := discord.NewEmbedBuilder().SetTitle("Lavalink nodes")
+
+	for _, config := range CONFIG.Load().Lavalink.Nodes {
+		node := CLIENT.Lavalink.Node(config.Name)
+		if node == nil {
+			embed.AddField(config.Name, "Not connected", false)
+			continue
+		}
+
+		stats := node.Stats()
+
+		players := 0
+		CLIENT.Lavalink.ForPlayers(func(player disgolink.Player) {
+			if player.Node() != nil && player.Node().Config().Name == config.Name {
+				players++
+			}
+		})
+
+		region := config.Region
+		if region == "" {
+			region = "any"
+		}
+
+		status := fmt.Sprintf("**Status:** %s\n**Region:** %s\n**Players:** %d total, %d playing, %d from this bot\n**Load:** %.1f%%\n**Uptime:** %s",
+			node.Status(), region, stats.Players, stats.PlayingPlayers, players, stats.CPU.SystemLoad*100, formatDuration(stats.Uptime))
+
+		embed.AddField(config.Name, status, false)
+	}
+
+	message := discord.NewMessageCreateBuilder().SetEmbeds(embed.Build()).SetEphemeral(true).Build()
+	sendMessage(event, message)
+}
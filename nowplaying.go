@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+func nowPlayingEmbed(guildID snowflake.ID) discord.Embed {
+	player := CLIENT.Lavalink.Player(guildID)
+	track := TRACKS.First()
+
+	status := "Playing"
+	if player.Paused() {
+		status = "Paused"
+	}
+
+	embed := discord.EmbedBuilder{Embed: TRACKS.GetTrackEmbed(guildID, track)}
+	embed.SetDescription(progressBar(player.Position(), track))
+	embed.AddField("Status", status, true)
+	embed.AddField("Queue", fmt.Sprintf("%d tracks", TRACKS.Len()-1), true)
+
+	votes := len(GUILDS.Get(guildID).SkipVotes)
+	if votes > 0 {
+		required := requiredSkipVotes(len(voiceListeners(guildID)))
+		embed.AddField("Skip votes", fmt.Sprintf("%d/%d", votes, required), true)
+	}
+
+	return embed.Build()
+}
+
+func nowPlayingButtons(guildID snowflake.ID) []discord.InteractiveComponent {
+	toggle := discord.NewSecondaryButton("Pause", "player:pause")
+	if CLIENT.Lavalink.Player(guildID).Paused() {
+		toggle = discord.NewSuccessButton("Resume", "player:resume")
+	}
+
+	return []discord.InteractiveComponent{
+		toggle,
+		discord.NewPrimaryButton("Skip", "player:skip"),
+		discord.NewDangerButton("Stop", "player:stop"),
+		discord.NewSecondaryButton("Queue", "player:queue"),
+	}
+}
+
+// Replaces the guild's now playing panel with a new one sent as the response to the interaction, with the content above it.
+func sendNowPlaying(event Interaction, content string) {
+	guildID := *event.GuildID()
+
+	if TRACKS.Empty() {
+		reply(event, "No tracks currently playing.")
+		return
+	}
+
+	state := GUILDS.Get(guildID)
+	if state.PanelMessageID != 0 {
+		err := CLIENT.Rest.DeleteMessage(state.PanelChannelID, state.PanelMessageID)
+		if err != nil {
+			eventLogger(event).Error("Error deleting now playing panel", "err", err)
+		}
+	}
+
+	message := discord.NewMessageCreateBuilder().
+		SetContent(content).
+		SetEmbeds(nowPlayingEmbed(guildID)).
+		AddActionRow(nowPlayingButtons(guildID)...).
+		Build()
+
+	err := event.CreateMessage(message)
+	if err != nil {
+		eventLogger(event).Error("Error replying", "err", err)
+		return
+	}
+
+	panel, err := CLIENT.Rest.GetInteractionResponse(event.ApplicationID(), event.Token())
+	if err != nil {
+		eventLogger(event).Error("Error getting now playing panel", "err", err)
+		return
+	}
+
+	GUILDS.Update(guildID, func(state *GuildState) {
+		state.PanelChannelID = panel.ChannelID
+		state.PanelMessageID = panel.ID
+	})
+}
+
+// Edits the guild's now playing panel to match the player, removing its buttons once nothing is playing.
+func refreshNowPlaying(guildID snowflake.ID) {
+	state := GUILDS.Get(guildID)
+	if state.PanelMessageID == 0 {
+		return
+	}
+
+	message := discord.NewMessageUpdateBuilder()
+
+	if TRACKS.Empty() {
+		message.SetContent("Nothing is playing.").ClearEmbeds().ClearContainerComponents()
+
+		GUILDS.Update(guildID, func(state *GuildState) {
+			state.PanelChannelID = 0
+			state.PanelMessageID = 0
+		})
+	} else {
+		message.SetEmbeds(nowPlayingEmbed(guildID)).SetContainerComponents(discord.NewActionRow(nowPlayingButtons(guildID)...))
+	}
+
+	_, err := CLIENT.Rest.UpdateMessage(state.PanelChannelID, state.PanelMessageID, message.Build())
+	if err != nil {
+		guildLogger(guildID).Error("Error updating now playing panel", "err", err)
+	}
+}
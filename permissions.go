@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// Checks that the user is in the bot's voice channel, replying with a denial if not.
+// Passes when the bot is not in a voice channel, the commands themselves handle that.
+func requireSameChannel(event Interaction) bool {
+	guildID := *event.GuildID()
+
+	channelID := botVoiceChannel(guildID)
+	if channelID == nil {
+		return true
+	}
+
+	voice, ok := CLIENT.Bot.Caches().VoiceState(guildID, event.User().ID)
+	if ok && voice.ChannelID != nil && *voice.ChannelID == *channelID {
+		return true
+	}
+
+	replyEphemeral(event, "You need to be in the same voice channel as the bot to use this.")
+	return false
+}
+
+// Users with the DJ role or administrators.
+func isDJ(event Interaction) bool {
+	member := event.Member()
+	if member == nil {
+		return false
+	}
+
+	if member.Permissions.Has(discord.PermissionAdministrator) {
+		return true
+	}
+
+	role := SETTINGS.Get(member.GuildID).DJRole
+	if role == 0 {
+		role = CONFIG.Load().Music.DJRole
+	}
+
+	if role == 0 {
+		return false
+	}
+
+	for _, roleID := range member.RoleIDs {
+		if roleID == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func setDJRole(event *events.ApplicationCommandInteractionCreate) {
+	guildID := *event.GuildID()
+
+	role := snowflake.ID(0)
+	selected, ok := event.SlashCommandInteractionData().OptRole("role")
+	if ok {
+		role = selected.ID
+	}
+
+	err := SETTINGS.Update(guildID, func(settings *GuildSettings) {
+		settings.DJRole = role
+	})
+	if err != nil {
+		eventLogger(event).Error("Error saving settings", "err", err)
+		reply(event, err.Error())
+		return
+	}
+
+	if role == 0 {
+		reply(event, "DJ role removed.")
+		return
+	}
+
+	reply(event, fmt.Sprintf("`%s` is now the DJ role.", selected.Name))
+}
+
+// DJs, or members who can manage the server, can change the guild's music settings.
+func canManageMusic(event Interaction) bool {
+	if isDJ(event) {
+		return true
+	}
+
+	member := event.Member()
+	return member != nil && member.Permissions.Has(discord.PermissionManageGuild)
+}
+
+func isRequester(event Interaction, track lavalink.Track) bool {
+	user := UserInfo{}
+	err := track.UserData.Unmarshal(&user)
+	if err != nil {
+		return false
+	}
+
+	return user.UserID == event.User().ID
+}
+
+// DJs and whoever requested the current track don't need votes to control it.
+func canBypassVotes(event Interaction) bool {
+	if isDJ(event) {
+		return true
+	}
+
+	return !TRACKS.Empty() && isRequester(event, TRACKS.First())
+}
+
+// The application owner, or the team owner and members if it belongs to a team.
+func isOwner(event Interaction) bool {
+	application, err := CLIENT.Rest.GetCurrentApplication()
+	if err != nil {
+		eventLogger(event).Error("Error getting application", "err", err)
+		return false
+	}
+
+	userID := event.User().ID
+
+	if application.Team != nil {
+		if application.Team.OwnerID == userID {
+			return true
+		}
+
+		for _, member := range application.Team.Members {
+			if member.User.ID == userID {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return application.Owner != nil && application.Owner.ID == userID
+}
+
+var (
+	DJ_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name:        "dj",
+				Description: "Sets the role that can control music without votes",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Sets the role that can control music without votes",
+					discord.LocalePortugueseBR: "Define o cargo que controla as músicas sem votação",
+				},
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionRole{
+						Name: "role",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "role",
+							discord.LocalePortugueseBR: "cargo",
+						},
+						Description: "The DJ role, leave empty to remove it",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "The DJ role, leave empty to remove it",
+							discord.LocalePortugueseBR: "O cargo de DJ, deixe vazio para removê-lo",
+						},
+					},
+				},
+			},
+			Handler:     setDJRole,
+			Category:    CATEGORY_MUSIC,
+			Feature:     FEATURE_MUSIC,
+			GuildOnly:   true,
+			Permissions: discord.PermissionManageGuild,
+			Examples:    []string{"/dj role:@DJ", "/dj"},
+		},
+	}
+)
@@ -0,0 +1,592 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/go-json-experiment/json"
+)
+
+const (
+	PLAYLISTS_PATH = "playlists.json"
+
+	PLAYLIST_MAX_TRACKS = 100
+)
+
+var (
+	ErrPlaylistExists   = errors.New("you already have a playlist with that name")
+	ErrPlaylistNotFound = errors.New("you don't have a playlist with that name")
+	ErrPlaylistFull     = fmt.Errorf("playlists can have at most %d tracks", PLAYLIST_MAX_TRACKS)
+	ErrPlaylistEmpty    = errors.New("the playlist is empty")
+	ErrInvalidTrack     = errors.New("there is no track at that position")
+)
+
+type Playlist struct {
+	Name   string          `json:"name"`
+	Tracks []PlaylistTrack `json:"tracks"`
+}
+
+type PlaylistTrack struct {
+	Encoded string `json:"encoded"`
+	Title   string `json:"title"`
+	URI     string `json:"uri"`
+}
+
+// Saved playlists by user.
+type Playlists struct {
+	store map[snowflake.ID][]Playlist
+	mu    sync.Mutex
+}
+
+func NewPlaylists() error {
+	_, err := os.Stat(CONFIG.Load().Storage.Playlists)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	slog.Info("Reading playlists file")
+	file, err := os.ReadFile(CONFIG.Load().Storage.Playlists)
+	if err != nil {
+		return err
+	}
+
+	PLAYLISTS.mu.Lock()
+	defer PLAYLISTS.mu.Unlock()
+
+	return json.Unmarshal(file, &PLAYLISTS.store)
+}
+
+func (p *Playlists) Get(userID snowflake.ID, name string) (Playlist, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	index := p.index(userID, name)
+	if index == -1 {
+		return Playlist{}, false
+	}
+
+	return p.store[userID][index], true
+}
+
+func (p *Playlists) All(userID snowflake.ID) []Playlist {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.store[userID])
+}
+
+func (p *Playlists) Create(userID snowflake.ID, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.index(userID, name) != -1 {
+		return ErrPlaylistExists
+	}
+
+	p.store[userID] = append(p.store[userID], Playlist{Name: name})
+	return p.save()
+}
+
+func (p *Playlists) Delete(userID snowflake.ID, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	index := p.index(userID, name)
+	if index == -1 {
+		return ErrPlaylistNotFound
+	}
+
+	p.store[userID] = slices.Delete(p.store[userID], index, index+1)
+	return p.save()
+}
+
+// Applies the update to the user's playlist and saves the playlists to disk.
+func (p *Playlists) Update(userID snowflake.ID, name string, update func(playlist *Playlist) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	index := p.index(userID, name)
+	if index == -1 {
+		return ErrPlaylistNotFound
+	}
+
+	err := update(&p.store[userID][index])
+	if err != nil {
+		return err
+	}
+
+	return p.save()
+}
+
+// Names are case insensitive. Must be called with the lock held.
+func (p *Playlists) index(userID snowflake.ID, name string) int {
+	return slices.IndexFunc(p.store[userID], func(playlist Playlist) bool {
+		return strings.EqualFold(playlist.Name, name)
+	})
+}
+
+// Must be called with the lock held.
+func (p *Playlists) save() error {
+	file, err := json.Marshal(p.store)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(CONFIG.Load().Storage.Playlists, file, 0644)
+}
+
+// Subcommands that only take the playlist name.
+func playlistHandler(handler func(event *events.ApplicationCommandInteractionCreate, name string)) CommandHandler {
+	return func(event *events.ApplicationCommandInteractionCreate) {
+		name, _ := event.SlashCommandInteractionData().OptString("name")
+		handler(event, name)
+	}
+}
+
+func playlistAddCommand(event *events.ApplicationCommandInteractionCreate) {
+	data := event.SlashCommandInteractionData()
+	name, _ := data.OptString("name")
+	query, _ := data.OptString("query")
+
+	playlistAdd(event, name, query)
+}
+
+func playlistRemoveCommand(event *events.ApplicationCommandInteractionCreate) {
+	data := event.SlashCommandInteractionData()
+	name, _ := data.OptString("name")
+	position, _ := data.OptInt("position")
+
+	playlistRemove(event, name, position)
+}
+
+func playlistCreate(event *events.ApplicationCommandInteractionCreate, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		replyEphemeral(event, "Please provide a playlist name.")
+		return
+	}
+
+	err := PLAYLISTS.Create(event.User().ID, name)
+	if err != nil {
+		replyEphemeral(event, err.Error())
+		return
+	}
+
+	replyEphemeral(event, fmt.Sprintf("Created playlist `%s`.", name))
+}
+
+func playlistDelete(event *events.ApplicationCommandInteractionCreate, name string) {
+	err := PLAYLISTS.Delete(event.User().ID, name)
+	if err != nil {
+		replyEphemeral(event, err.Error())
+		return
+	}
+
+	replyEphemeral(event, fmt.Sprintf("Deleted playlist `%s`.", name))
+}
+
+// Adds the track found by the query, or the current track if there is no query.
+func playlistAdd(event *events.ApplicationCommandInteractionCreate, name string, query string) {
+	var track lavalink.Track
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		if TRACKS.Empty() {
+			replyEphemeral(event, "No tracks currently playing.")
+			return
+		}
+
+		track = TRACKS.First()
+	} else {
+		found, err := loadQuery(*event.GuildID(), newUserInfo(event), query, "")
+		if err != nil {
+			replyEphemeral(event, err.Error())
+			return
+		}
+
+		track = found
+	}
+
+	if isAttachmentTrack(track) {
+		replyEphemeral(event, "Uploaded files can't be added to playlists, their links expire.")
+		return
+	}
+
+	uri := ""
+	if track.Info.URI != nil {
+		uri = *track.Info.URI
+	}
+
+	err := PLAYLISTS.Update(event.User().ID, name, func(playlist *Playlist) error {
+		if len(playlist.Tracks) >= PLAYLIST_MAX_TRACKS {
+			return ErrPlaylistFull
+		}
+
+		playlist.Tracks = append(playlist.Tracks, PlaylistTrack{
+			Encoded: track.Encoded,
+			Title:   trackTitle(track),
+			URI:     uri,
+		})
+		return nil
+	})
+	if err != nil {
+		replyEphemeral(event, err.Error())
+		return
+	}
+
+	replyEphemeral(event, fmt.Sprintf("Added %s to `%s`.", trackTitle(track), name))
+}
+
+func playlistRemove(event *events.ApplicationCommandInteractionCreate, name string, position int) {
+	title := ""
+
+	err := PLAYLISTS.Update(event.User().ID, name, func(playlist *Playlist) error {
+		if position < 1 || position > len(playlist.Tracks) {
+			return ErrInvalidTrack
+		}
+
+		title = playlist.Tracks[position-1].Title
+		playlist.Tracks = slices.Delete(playlist.Tracks, position-1, position)
+		return nil
+	})
+	if err != nil {
+		replyEphemeral(event, err.Error())
+		return
+	}
+
+	replyEphemeral(event, fmt.Sprintf("Removed %s from `%s`.", title, name))
+}
+
+// Shows the tracks of a playlist, or lists the user's playlists if no name is given.
+func playlistShow(event *events.ApplicationCommandInteractionCreate, name string) {
+	embed := discord.NewEmbedBuilder()
+
+	if name == "" {
+		playlists := PLAYLISTS.All(event.User().ID)
+
+		lines := make([]string, 0, len(playlists))
+		for _, playlist := range playlists {
+			lines = append(lines, fmt.Sprintf("`%s` - %d tracks", playlist.Name, len(playlist.Tracks)))
+		}
+
+		if len(lines) == 0 {
+			lines = append(lines, "You have no playlists, create one with `/playlist create`.")
+		}
+
+		embed.SetTitle("Your playlists").SetDescription(strings.Join(lines, "\n"))
+	} else {
+		playlist, ok := PLAYLISTS.Get(event.User().ID, name)
+		if !ok {
+			replyEphemeral(event, ErrPlaylistNotFound.Error())
+			return
+		}
+
+		lines := make([]string, 0, len(playlist.Tracks))
+		for i, track := range playlist.Tracks {
+			line := fmt.Sprintf("`%d.` %s", i+1, track.Title)
+			if track.URI != "" {
+				line = fmt.Sprintf("`%d.` [%s](%s)", i+1, track.Title, track.URI)
+			}
+
+			lines = append(lines, line)
+		}
+
+		if len(lines) == 0 {
+			lines = append(lines, "No tracks yet, add some with `/playlist add`.")
+		}
+
+		// Embed descriptions are limited to 4096 characters
+		description := strings.Join(lines, "\n")
+		if len(description) > 4000 {
+			// Cut at the last full line, or mid line without splitting a character if the first one is too long
+			cut := strings.LastIndex(description[:4000], "\n")
+			if cut == -1 {
+				cut = 4000
+				for !utf8.RuneStart(description[cut]) {
+					cut--
+				}
+			}
+
+			description = description[:cut] + "\n..."
+		}
+
+		embed.SetTitle(playlist.Name).SetDescription(description)
+	}
+
+	message := discord.NewMessageCreateBuilder().SetEmbeds(embed.Build()).SetEphemeral(true).Build()
+	sendMessage(event, message)
+}
+
+func playlistPlay(event *events.ApplicationCommandInteractionCreate, name string) {
+	playlist, ok := PLAYLISTS.Get(event.User().ID, name)
+	if !ok {
+		replyEphemeral(event, ErrPlaylistNotFound.Error())
+		return
+	}
+
+	if len(playlist.Tracks) == 0 {
+		replyEphemeral(event, ErrPlaylistEmpty.Error())
+		return
+	}
+
+	encoded := make([]string, 0, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		encoded = append(encoded, track.Encoded)
+	}
+
+	decoded, err := loadingNode().DecodeTracks(context.TODO(), encoded)
+	if err != nil {
+		eventLogger(event).Error("Error decoding playlist", "err", err)
+		reply(event, ErrLoadingTracks.Error())
+		return
+	}
+
+	user := newUserInfo(event)
+	tracks := make([]lavalink.Track, 0, len(decoded))
+
+	for _, track := range decoded {
+		track, err = track.WithUserData(user)
+		if err != nil {
+			eventLogger(event).Error("Error adding user data", "err", err)
+			continue
+		}
+
+		tracks = append(tracks, track)
+	}
+
+	enqueue(event, tracks...)
+}
+
+var (
+	PLAYLIST_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name:        "playlist",
+				Description: "Manages your saved playlists",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Manages your saved playlists",
+					discord.LocalePortugueseBR: "Gerencia suas playlists salvas",
+				},
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionSubCommand{
+						Name: "create",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "create",
+							discord.LocalePortugueseBR: "criar",
+						},
+						Description: "Creates a playlist",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Creates a playlist",
+							discord.LocalePortugueseBR: "Cria uma playlist",
+						},
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name: "name",
+								NameLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "name",
+									discord.LocalePortugueseBR: "nome",
+								},
+								Description: "Playlist name",
+								DescriptionLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "Playlist name",
+									discord.LocalePortugueseBR: "Nome da playlist",
+								},
+								MaxLength: &PLAYLIST_NAME_LENGTH,
+								Required:  true,
+							},
+						},
+					},
+					discord.ApplicationCommandOptionSubCommand{
+						Name: "add",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "add",
+							discord.LocalePortugueseBR: "adicionar",
+						},
+						Description: "Adds a track to a playlist",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Adds a track to a playlist",
+							discord.LocalePortugueseBR: "Adiciona uma música a uma playlist",
+						},
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name: "name",
+								NameLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "name",
+									discord.LocalePortugueseBR: "nome",
+								},
+								Description: "Playlist name",
+								DescriptionLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "Playlist name",
+									discord.LocalePortugueseBR: "Nome da playlist",
+								},
+								MaxLength: &PLAYLIST_NAME_LENGTH,
+								Required:  true,
+							},
+							discord.ApplicationCommandOptionString{
+								Name: "query",
+								NameLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "query",
+									discord.LocalePortugueseBR: "consulta",
+								},
+								Description: "URL or search query, adds the current track if empty",
+								DescriptionLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "URL or search query, adds the current track if empty",
+									discord.LocalePortugueseBR: "URL ou pesquisa, adiciona a música atual se vazio",
+								},
+							},
+						},
+					},
+					discord.ApplicationCommandOptionSubCommand{
+						Name: "remove",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "remove",
+							discord.LocalePortugueseBR: "remover",
+						},
+						Description: "Removes a track from a playlist",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Removes a track from a playlist",
+							discord.LocalePortugueseBR: "Remove uma música de uma playlist",
+						},
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name: "name",
+								NameLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "name",
+									discord.LocalePortugueseBR: "nome",
+								},
+								Description: "Playlist name",
+								DescriptionLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "Playlist name",
+									discord.LocalePortugueseBR: "Nome da playlist",
+								},
+								MaxLength: &PLAYLIST_NAME_LENGTH,
+								Required:  true,
+							},
+							discord.ApplicationCommandOptionInt{
+								Name: "position",
+								NameLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "position",
+									discord.LocalePortugueseBR: "posicao",
+								},
+								Description: "Position of the track in the playlist",
+								DescriptionLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "Position of the track in the playlist",
+									discord.LocalePortugueseBR: "Posição da música na playlist",
+								},
+								MinValue: &MIN_POSITION,
+								Required: true,
+							},
+						},
+					},
+					discord.ApplicationCommandOptionSubCommand{
+						Name: "show",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "show",
+							discord.LocalePortugueseBR: "mostrar",
+						},
+						Description: "Shows a playlist or lists your playlists",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Shows a playlist or lists your playlists",
+							discord.LocalePortugueseBR: "Mostra uma playlist ou lista suas playlists",
+						},
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name: "name",
+								NameLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "name",
+									discord.LocalePortugueseBR: "nome",
+								},
+								Description: "Playlist name, lists your playlists if empty",
+								DescriptionLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "Playlist name, lists your playlists if empty",
+									discord.LocalePortugueseBR: "Nome da playlist, lista suas playlists se vazio",
+								},
+								MaxLength: &PLAYLIST_NAME_LENGTH,
+								Required:  false,
+							},
+						},
+					},
+					discord.ApplicationCommandOptionSubCommand{
+						Name: "play",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "play",
+							discord.LocalePortugueseBR: "tocar",
+						},
+						Description: "Queues a whole playlist",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Queues a whole playlist",
+							discord.LocalePortugueseBR: "Adiciona uma playlist inteira à fila",
+						},
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name: "name",
+								NameLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "name",
+									discord.LocalePortugueseBR: "nome",
+								},
+								Description: "Playlist name",
+								DescriptionLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "Playlist name",
+									discord.LocalePortugueseBR: "Nome da playlist",
+								},
+								MaxLength: &PLAYLIST_NAME_LENGTH,
+								Required:  true,
+							},
+						},
+					},
+					discord.ApplicationCommandOptionSubCommand{
+						Name: "delete",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "delete",
+							discord.LocalePortugueseBR: "apagar",
+						},
+						Description: "Deletes a playlist",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Deletes a playlist",
+							discord.LocalePortugueseBR: "Apaga uma playlist",
+						},
+						Options: []discord.ApplicationCommandOption{
+							discord.ApplicationCommandOptionString{
+								Name: "name",
+								NameLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "name",
+									discord.LocalePortugueseBR: "nome",
+								},
+								Description: "Playlist name",
+								DescriptionLocalizations: map[discord.Locale]string{
+									discord.LocaleEnglishUS:    "Playlist name",
+									discord.LocalePortugueseBR: "Nome da playlist",
+								},
+								MaxLength: &PLAYLIST_NAME_LENGTH,
+								Required:  true,
+							},
+						},
+					},
+				},
+			},
+			Subcommands: map[string]CommandHandler{
+				"create": playlistHandler(playlistCreate),
+				"delete": playlistHandler(playlistDelete),
+				"add":    playlistAddCommand,
+				"remove": playlistRemoveCommand,
+				"show":   playlistHandler(playlistShow),
+				"play":   playlistHandler(playlistPlay),
+			},
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+			Examples:  []string{"/playlist create name:chill", "/playlist add name:chill query:lofi", "/playlist add name:chill", "/playlist remove name:chill position:2", "/playlist play name:chill"},
+		},
+	}
+)
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/go-json-experiment/json"
+)
+
+const (
+	IMPORT_MAX_SIZE    = 1024 * 1024
+	IMPORT_MAX_ENTRIES = 200
+
+	// Entries resolved between each progress update
+	IMPORT_PROGRESS_INTERVAL = 5
+
+	// Entries loaded at the same time, each can try every source
+	IMPORT_WORKERS = 5
+)
+
+type QueueEntry struct {
+	URI    string `json:"uri"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	// In milliseconds, -1 for streams
+	Length    int64  `json:"length"`
+	Requester string `json:"requester"`
+}
+
+// URIs are loaded directly, entries without one are searched by author and title.
+func (e QueueEntry) query() string {
+	if strings.HasPrefix(e.URI, "http") || e.Title == "" {
+		return e.URI
+	}
+
+	return strings.TrimSpace(e.Author + " " + e.Title)
+}
+
+func (e QueueEntry) name() string {
+	if e.Title != "" {
+		return e.Title
+	}
+
+	return e.URI
+}
+
+func queueEntries() []QueueEntry {
+	tracks := TRACKS.All()
+	entries := make([]QueueEntry, 0, len(tracks))
+
+	for _, track := range tracks {
+		// Their links expire, the file would not load later
+		if isAttachmentTrack(track) {
+			continue
+		}
+
+		user := UserInfo{}
+		err := track.UserData.Unmarshal(&user)
+		if err != nil {
+			slog.Error("Error scanning user", "err", err)
+		}
+
+		uri := ""
+		if track.Info.URI != nil {
+			uri = *track.Info.URI
+		}
+
+		length := track.Info.Length.Milliseconds()
+		if track.Info.IsStream {
+			length = -1
+		}
+
+		entries = append(entries, QueueEntry{
+			URI:       uri,
+			Title:     trackTitle(track),
+			Author:    track.Info.Author,
+			Length:    length,
+			Requester: user.Username,
+		})
+	}
+
+	return entries
+}
+
+func formatM3U(entries []QueueEntry) string {
+	var m3u strings.Builder
+	m3u.WriteString("#EXTM3U\n")
+
+	for _, entry := range entries {
+		seconds := entry.Length
+		if seconds > 0 {
+			seconds /= 1000
+		}
+
+		fmt.Fprintf(&m3u, "#EXTINF:%d,%s - %s\n%s\n", seconds, entry.Author, entry.Title, entry.URI)
+	}
+
+	return m3u.String()
+}
+
+// Reads the entries of an extended M3U playlist, the EXTINF line before an URI is used as its title.
+func parseM3U(file []byte) []QueueEntry {
+	entries := []QueueEntry{}
+	title := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(file))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "#EXTINF:") {
+			_, title, _ = strings.Cut(line, ",")
+			continue
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		entries = append(entries, QueueEntry{URI: line, Title: strings.TrimSpace(title)})
+		title = ""
+	}
+
+	return entries
+}
+
+func exportQueue(event *events.ApplicationCommandInteractionCreate) {
+	if TRACKS.Empty() {
+		reply(event, "No tracks currently playing.")
+		return
+	}
+
+	entries := queueEntries()
+
+	file, err := json.Marshal(entries)
+	if err != nil {
+		eventLogger(event).Error("Error exporting queue", "err", err)
+		reply(event, err.Error())
+		return
+	}
+
+	message := discord.NewMessageCreateBuilder().
+		SetContent(fmt.Sprintf("Exported %d tracks.", len(entries))).
+		AddFile("queue.m3u8", "Queue as a M3U playlist", strings.NewReader(formatM3U(entries))).
+		AddFile("queue.json", "Queue as JSON", bytes.NewReader(file)).
+		Build()
+
+	sendMessage(event, message)
+}
+
+// Loads every entry of an exported M3U or JSON queue and queues the ones that could be loaded.
+func importQueueCommand(event *events.ApplicationCommandInteractionCreate) {
+	file, ok := event.SlashCommandInteractionData().OptAttachment("file")
+	if !ok {
+		reply(event, "Please provide a file.")
+		return
+	}
+
+	importQueue(event, file)
+}
+
+func importQueue(event *events.ApplicationCommandInteractionCreate, attachment discord.Attachment) {
+	guildID := *event.GuildID()
+
+	userVoice, err := CLIENT.Rest.GetUserVoiceState(guildID, event.User().ID)
+	if userVoice == nil || err != nil {
+		reply(event, "You are not in a voice channel.")
+		return
+	}
+
+	if attachment.Size > IMPORT_MAX_SIZE {
+		replyEphemeral(event, "The file is too big.")
+		return
+	}
+
+	extension := strings.ToLower(path.Ext(attachment.Filename))
+	if extension != ".json" && extension != ".m3u" && extension != ".m3u8" {
+		replyEphemeral(event, "Only `.m3u`, `.m3u8` and `.json` files can be imported.")
+		return
+	}
+
+	// Loading the entries takes longer than the time given to respond
+	err = event.DeferCreateMessage(false)
+	if err != nil {
+		eventLogger(event).Error("Error deferring", "err", err)
+		return
+	}
+
+	// Loaded in the background, the event listeners would be blocked until every entry is done
+	go loadImport(event, attachment, extension)
+}
+
+// Downloads and loads the entries of the file, replying through the deferred response.
+func loadImport(event *events.ApplicationCommandInteractionCreate, attachment discord.Attachment, extension string) {
+	guildID := *event.GuildID()
+	deferred := DeferredInteraction{event}
+
+	res, err := HTTP.Get(attachment.URL)
+	if err != nil {
+		eventLogger(event).Error("Error downloading queue", "err", err)
+		reply(deferred, err.Error())
+		return
+	}
+	defer res.Body.Close()
+
+	file, err := io.ReadAll(io.LimitReader(res.Body, IMPORT_MAX_SIZE))
+	if err != nil {
+		eventLogger(event).Error("Error reading queue", "err", err)
+		reply(deferred, err.Error())
+		return
+	}
+
+	entries := []QueueEntry{}
+	if extension == ".json" {
+		err = json.Unmarshal(file, &entries)
+		if err != nil {
+			eventLogger(event).Error("Error parsing queue", "err", err)
+			reply(deferred, "The file is not a valid queue export.")
+			return
+		}
+	} else {
+		entries = parseM3U(file)
+	}
+
+	// Entries with nothing to search for
+	entries = slices.DeleteFunc(entries, func(entry QueueEntry) bool {
+		return entry.query() == ""
+	})
+
+	if len(entries) == 0 {
+		reply(deferred, "The file has no entries.")
+		return
+	}
+
+	if len(entries) > IMPORT_MAX_ENTRIES {
+		entries = entries[:IMPORT_MAX_ENTRIES]
+	}
+
+	user := newUserInfo(event)
+	results := make([]lavalink.Track, len(entries))
+	errs := make([]error, len(entries))
+
+	// Workers load the entries in parallel, keeping their order in results
+	indexes := make(chan int)
+	done := make(chan struct{})
+
+	for range IMPORT_WORKERS {
+		go func() {
+			for i := range indexes {
+				results[i], errs[i] = loadQuery(guildID, user, entries[i].query(), "")
+				done <- struct{}{}
+			}
+		}()
+	}
+
+	go func() {
+		for i := range entries {
+			indexes <- i
+		}
+		close(indexes)
+	}()
+
+	for i := range entries {
+		if i%IMPORT_PROGRESS_INTERVAL == 0 {
+			reply(deferred, fmt.Sprintf("Importing... %d/%d", i, len(entries)))
+		}
+
+		<-done
+	}
+
+	tracks := make([]lavalink.Track, 0, len(entries))
+	failed := []string{}
+
+	for i, entry := range entries {
+		if errs[i] != nil {
+			failed = append(failed, entry.name())
+			continue
+		}
+
+		tracks = append(tracks, results[i])
+	}
+
+	if len(tracks) == 0 {
+		reply(deferred, "None of the entries could be loaded.")
+	} else {
+		enqueue(deferred, tracks...)
+	}
+
+	if len(failed) == 0 {
+		return
+	}
+
+	summary := fmt.Sprintf("Could not load %d entries:", len(failed))
+	for i, name := range failed {
+		line := "\n- " + name
+
+		// Messages are limited to 2000 characters
+		if len(summary)+len(line) > 1900 {
+			summary += fmt.Sprintf("\n... and %d more", len(failed)-i)
+			break
+		}
+
+		summary += line
+	}
+
+	_, err = CLIENT.Rest.CreateFollowupMessage(event.ApplicationID(), event.Token(), discord.NewMessageCreateBuilder().SetContent(summary).Build())
+	if err != nil {
+		eventLogger(event).Error("Error sending import failures", "err", err)
+	}
+}
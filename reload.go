@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/gateway"
+)
+
+// Loads the config file again and swaps it in. Changes to fields that are only read when starting
+// are not applied, the running values are kept and the fields are returned so they can be reported.
+func reloadConfig() ([]string, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	old := CONFIG.Load()
+	restart := []string{}
+
+	if config.Discord.Token != old.Discord.Token {
+		restart = append(restart, "discord.token")
+	}
+
+	if !slices.Equal(config.Discord.Intents, old.Discord.Intents) {
+		restart = append(restart, "discord.intents")
+	}
+
+	if config.Discord.DevGuild != old.Discord.DevGuild {
+		restart = append(restart, "discord.dev_guild")
+	}
+
+	if !slices.Equal(config.Lavalink.Nodes, old.Lavalink.Nodes) {
+		restart = append(restart, "lavalink.nodes")
+	}
+
+	if config.Storage != old.Storage {
+		restart = append(restart, "storage")
+	}
+
+	if config.Features != old.Features {
+		restart = append(restart, "features")
+	}
+
+	if config.Logging.Format != old.Logging.Format {
+		restart = append(restart, "logging.format")
+	}
+
+	if config.HTTP != old.HTTP {
+		restart = append(restart, "http")
+	}
+
+	config.Discord.Token = old.Discord.Token
+	config.Discord.Intents = old.Discord.Intents
+	config.Discord.DevGuild = old.Discord.DevGuild
+	config.Lavalink = old.Lavalink
+	config.Storage = old.Storage
+	config.Features = old.Features
+	config.Logging.Format = old.Logging.Format
+	config.HTTP = old.HTTP
+
+	CONFIG.Store(&config)
+
+	LOG_LEVEL.Set(logLevel(config.Logging.Level))
+
+	if config.AssetsDir != old.AssetsDir {
+		_, err = loadAssets()
+		if err != nil {
+			slog.Error("Error loading assets", "err", err)
+		}
+
+		// The joel command choices only change when registering the commands
+		restart = append(restart, "assets_dir (joel choices, with -register)")
+	}
+
+	if config.Discord.Presence != old.Discord.Presence {
+		err = CLIENT.Bot.SetPresence(context.TODO(),
+			gateway.WithPlayingActivity(config.Discord.Presence.Text),
+			gateway.WithOnlineStatus(ONLINE_STATUSES[config.Discord.Presence.Status]),
+		)
+		if err != nil {
+			slog.Error("Error updating presence", "err", err)
+		}
+	}
+
+	slog.Info("Config reloaded")
+	return restart, nil
+}
+
+func adminReload(event *events.ApplicationCommandInteractionCreate) {
+	if !isOwner(event) {
+		replyEphemeral(event, "Only the bot owner can reload the config.")
+		return
+	}
+
+	restart, err := reloadConfig()
+	if err != nil {
+		eventLogger(event).Error("Error reloading config", "err", err)
+		replyEphemeral(event, fmt.Sprintf("The config was not reloaded:\n```\n%v\n```", err))
+		return
+	}
+
+	if len(restart) == 0 {
+		replyEphemeral(event, "Config reloaded.")
+		return
+	}
+
+	replyEphemeral(event, fmt.Sprintf("Config reloaded, these changes need a restart: %s.", strings.Join(restart, ", ")))
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/go-json-experiment/json"
+)
+
+const (
+	SETTINGS_PATH = "settings.json"
+)
+
+type GuildSettings struct {
+	Volume   int    `json:"volume"`
+	Filter   string `json:"filter"`
+	AlwaysOn bool   `json:"always_on"`
+	Autoplay bool   `json:"autoplay"`
+	Source   string `json:"source"`
+	// Members with this role can control music without votes
+	DJRole snowflake.ID `json:"dj_role"`
+}
+
+type Settings struct {
+	store map[snowflake.ID]GuildSettings
+	mu    sync.Mutex
+}
+
+func NewSettings() error {
+	_, err := os.Stat(CONFIG.Load().Storage.Settings)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	slog.Info("Reading settings file")
+	file, err := os.ReadFile(CONFIG.Load().Storage.Settings)
+	if err != nil {
+		return err
+	}
+
+	SETTINGS.mu.Lock()
+	defer SETTINGS.mu.Unlock()
+
+	return json.Unmarshal(file, &SETTINGS.store)
+}
+
+func defaultGuildSettings() GuildSettings {
+	return GuildSettings{
+		Volume: 100,
+		Filter: FILTER_OFF,
+		Source: SOURCE_YOUTUBE,
+	}
+}
+
+func (s *Settings) Get(guildID snowflake.ID) GuildSettings {
+	s.mu.Lock()
+	settings, ok := s.store[guildID]
+	s.mu.Unlock()
+
+	if !ok {
+		return defaultGuildSettings()
+	}
+
+	return settings
+}
+
+// Applies the update to the guild settings and saves them to disk.
+func (s *Settings) Update(guildID snowflake.ID, update func(settings *GuildSettings)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	settings, ok := s.store[guildID]
+	if !ok {
+		settings = defaultGuildSettings()
+	}
+
+	update(&settings)
+	s.store[guildID] = settings
+
+	file, err := json.Marshal(s.store)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(CONFIG.Load().Storage.Settings, file, 0644)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/disgoorg/disgolink/v3/disgolink"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	SHUTDOWN_TIMEOUT = 15 * time.Second
+
+	// Time the LLM request being processed has to finish before it's cancelled
+	LLM_DRAIN_TIMEOUT = 5 * time.Second
+)
+
+// Stops the bot in order: LLM requests, saving the state, voice and players, Lavalink and the gateway.
+// Exits anyway if it takes longer than SHUTDOWN_TIMEOUT.
+func shutdown() {
+	slog.Info("Shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), SHUTDOWN_TIMEOUT)
+	defer cancel()
+
+	timeout := time.AfterFunc(SHUTDOWN_TIMEOUT, func() {
+		slog.Error("Shutdown timed out")
+		os.Exit(1)
+	})
+	defer timeout.Stop()
+
+	close(LLM_STOP)
+
+	select {
+	case <-LLM_DONE:
+	case <-time.After(LLM_DRAIN_TIMEOUT):
+		slog.Warn("Cancelling the LLM request")
+		LLM_CANCEL()
+		<-LLM_DONE
+	}
+
+	if CLIENT.Lavalink != nil {
+		closeState()
+
+		guildIDs := []snowflake.ID{}
+		CLIENT.Lavalink.ForPlayers(func(player disgolink.Player) {
+			guildIDs = append(guildIDs, player.GuildID())
+		})
+
+		for _, guildID := range guildIDs {
+			err := CLIENT.Lavalink.Player(guildID).Destroy(ctx)
+			if err != nil {
+				slog.Error("Error destroying player", "err", err)
+			}
+
+			err = CLIENT.Bot.UpdateVoiceState(ctx, guildID, nil, false, false)
+			if err != nil {
+				slog.Error("Error leaving voice channel", "err", err)
+			}
+		}
+
+		CLIENT.Lavalink.Close()
+	}
+
+	CLIENT.Bot.Close(ctx)
+
+	closeHTTPServer(ctx)
+
+	slog.Info("Shut down")
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	SOURCE_YOUTUBE       = "youtube"
+	SOURCE_YOUTUBE_MUSIC = "youtubemusic"
+	SOURCE_SOUNDCLOUD    = "soundcloud"
+	SOURCE_BANDCAMP      = "bandcamp"
+)
+
+var (
+	// Also the order sources are tried in when one has no matches
+	SOURCES = []string{SOURCE_YOUTUBE, SOURCE_YOUTUBE_MUSIC, SOURCE_SOUNDCLOUD, SOURCE_BANDCAMP}
+
+	SOURCE_PREFIXES = map[string]string{
+		SOURCE_YOUTUBE:       "ytsearch:",
+		SOURCE_YOUTUBE_MUSIC: "ytmsearch:",
+		SOURCE_SOUNDCLOUD:    "scsearch:",
+		SOURCE_BANDCAMP:      "bcsearch:",
+	}
+)
+
+// Returns the guild's default search source, YouTube if none was set.
+func guildSource(guildID snowflake.ID) string {
+	source := SETTINGS.Get(guildID).Source
+
+	_, ok := SOURCE_PREFIXES[source]
+	if !ok {
+		return SOURCE_YOUTUBE
+	}
+
+	return source
+}
+
+// Loads URLs directly, searches start at the given source, or the guild's default,
+// and fall back to the other sources until one has a match.
+func loadQuery(guildID snowflake.ID, user UserInfo, query string, source string) (lavalink.Track, error) {
+	query = strings.TrimSpace(query)
+	if strings.HasPrefix(query, "http") {
+		return handleUserQuery(user, query)
+	}
+
+	_, ok := SOURCE_PREFIXES[source]
+	if !ok {
+		source = guildSource(guildID)
+	}
+
+	chain := []string{source}
+	for _, fallback := range SOURCES {
+		if fallback != source {
+			chain = append(chain, fallback)
+		}
+	}
+
+	// Reported if every source failed and at least one of them failed to load
+	result := ErrNoTracksFound
+
+	for _, source := range chain {
+		track, err := handleUserQuery(user, SOURCE_PREFIXES[source]+query)
+		switch err {
+		case ErrNoTracksFound:
+			slog.Debug("No matches, trying the next source", "source", source, "query", query)
+			continue
+		case ErrLoadingTracks:
+			slog.Debug("Loading failed, trying the next source", "source", source, "query", query)
+			result = ErrLoadingTracks
+			continue
+		}
+
+		return track, err
+	}
+
+	return lavalink.Track{}, result
+}
+
+func setSourceCommand(event *events.ApplicationCommandInteractionCreate) {
+	source, ok := event.SlashCommandInteractionData().OptString("source")
+	if !ok {
+		reply(event, "Please provide a source.")
+		return
+	}
+
+	setSource(event, source)
+}
+
+func setSource(event *events.ApplicationCommandInteractionCreate, source string) {
+	guildID := *event.GuildID()
+
+	if !canManageMusic(event) {
+		replyEphemeral(event, "Only DJs and members with the Manage Server permission can change the source.")
+		return
+	}
+
+	_, ok := SOURCE_PREFIXES[source]
+	if !ok {
+		reply(event, fmt.Sprintf("Unknown source `%s`.", source))
+		return
+	}
+
+	err := SETTINGS.Update(guildID, func(settings *GuildSettings) {
+		settings.Source = source
+	})
+	if err != nil {
+		eventLogger(event).Error("Error saving settings", "err", err)
+		reply(event, err.Error())
+		return
+	}
+
+	reply(event, fmt.Sprintf("Searches now use `%s` first.", source))
+}
+
+var (
+	SOURCE_COMMANDS = []Command{
+		{
+			Create: discord.SlashCommandCreate{
+				Name: "source",
+				NameLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "source",
+					discord.LocalePortugueseBR: "fonte",
+				},
+				Description: "Sets the default search source",
+				DescriptionLocalizations: map[discord.Locale]string{
+					discord.LocaleEnglishUS:    "Sets the default search source",
+					discord.LocalePortugueseBR: "Define a fonte de pesquisa padrão",
+				},
+				Options: []discord.ApplicationCommandOption{
+					discord.ApplicationCommandOptionString{
+						Name: "source",
+						NameLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "source",
+							discord.LocalePortugueseBR: "fonte",
+						},
+						Description: "Searched first, the others are tried when it has no matches",
+						DescriptionLocalizations: map[discord.Locale]string{
+							discord.LocaleEnglishUS:    "Searched first, the others are tried when it has no matches",
+							discord.LocalePortugueseBR: "Pesquisada primeiro, as outras são usadas quando não há resultados",
+						},
+						Choices: []discord.ApplicationCommandOptionChoiceString{
+							{Name: "YouTube", Value: SOURCE_YOUTUBE},
+							{Name: "YouTube Music", Value: SOURCE_YOUTUBE_MUSIC},
+							{Name: "SoundCloud", Value: SOURCE_SOUNDCLOUD},
+							{Name: "Bandcamp", Value: SOURCE_BANDCAMP},
+						},
+						Required: true,
+					},
+				},
+			},
+			Handler:   setSourceCommand,
+			Category:  CATEGORY_MUSIC,
+			Feature:   FEATURE_MUSIC,
+			GuildOnly: true,
+			Examples:  []string{"/source source:soundcloud"},
+		},
+	}
+)
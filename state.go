@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgolink/v3/disgolink"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/go-json-experiment/json"
+)
+
+const (
+	STATE_PATH = "state.json"
+
+	STATE_SAVE_INTERVAL = 15 * time.Second
+
+	// Seconds Lavalink keeps players alive after the bot disconnects
+	SESSION_RESUME_TIMEOUT = 60
+)
+
+// Music state saved to disk so playback can continue after a restart.
+type State struct {
+	// Lavalink session ID by node name
+	Sessions map[string]string `json:"sessions"`
+	// The bot has a single queue, saved for the guild playing it
+	Queue SavedQueue `json:"queue"`
+
+	// Set after the last save when shutting down
+	closed bool
+	mu     sync.Mutex
+}
+
+type SavedQueue struct {
+	GuildID        snowflake.ID      `json:"guild_id"`
+	VoiceChannelID snowflake.ID      `json:"voice_channel_id"`
+	TextChannelID  snowflake.ID      `json:"text_channel_id"`
+	Position       lavalink.Duration `json:"position"`
+	Paused         bool              `json:"paused"`
+	Tracks         []SavedTrack      `json:"tracks"`
+}
+
+type SavedTrack struct {
+	Encoded string   `json:"encoded"`
+	User    UserInfo `json:"user"`
+}
+
+func NewState() error {
+	_, err := os.Stat(CONFIG.Load().Storage.State)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	slog.Info("Reading state file")
+	file, err := os.ReadFile(CONFIG.Load().Storage.State)
+	if err != nil {
+		return err
+	}
+
+	STATE.mu.Lock()
+	defer STATE.mu.Unlock()
+
+	return json.Unmarshal(file, &STATE)
+}
+
+// Saves the queue, if the guild is the one playing it, and the Lavalink sessions to disk.
+func saveState(guildID snowflake.ID) {
+	queue := SavedQueue{GuildID: guildID}
+	player := CLIENT.Lavalink.ExistingPlayer(guildID)
+
+	if player != nil && !TRACKS.Empty() && playingQueue(player) {
+		channelID := botVoiceChannel(guildID)
+		if channelID != nil {
+			queue.VoiceChannelID = *channelID
+		}
+
+		queue.TextChannelID = GUILDS.Get(guildID).TextChannelID
+		queue.Position = player.Position()
+		queue.Paused = player.Paused()
+
+		for _, track := range TRACKS.All() {
+			user := UserInfo{}
+			err := track.UserData.Unmarshal(&user)
+			if err != nil {
+				guildLogger(guildID).Error("Error scanning user", "err", err)
+				continue
+			}
+
+			queue.Tracks = append(queue.Tracks, SavedTrack{
+				Encoded: track.Encoded,
+				User:    user,
+			})
+		}
+	}
+
+	STATE.mu.Lock()
+	defer STATE.mu.Unlock()
+
+	if STATE.closed {
+		return
+	}
+
+	// Saves from other guilds leave the queue alone, only the guild playing it clears it
+	if len(queue.Tracks) > 0 || STATE.Queue.GuildID == guildID {
+		STATE.Queue = queue
+	}
+
+	CLIENT.Lavalink.ForNodes(func(node disgolink.Node) {
+		STATE.Sessions[node.Config().Name] = node.SessionID()
+	})
+
+	file, err := json.Marshal(&STATE)
+	if err != nil {
+		guildLogger(guildID).Error("Error encoding state", "err", err)
+		return
+	}
+
+	err = os.WriteFile(CONFIG.Load().Storage.State, file, 0644)
+	if err != nil {
+		guildLogger(guildID).Error("Error saving state", "err", err)
+	}
+}
+
+// Saves the state of every guild with a player.
+func saveAllStates() {
+	CLIENT.Lavalink.ForPlayers(func(player disgolink.Player) {
+		saveState(player.GuildID())
+	})
+}
+
+// Saves every guild one last time, later saves are ignored so leaving the voice channels keeps the saved queue.
+func closeState() {
+	saveAllStates()
+
+	STATE.mu.Lock()
+	STATE.closed = true
+	STATE.mu.Unlock()
+}
+
+// Lets Lavalink keep the node's players playing while the bot reconnects.
+func enableSessionResuming(node disgolink.Node) error {
+	resuming := true
+	timeout := SESSION_RESUME_TIMEOUT
+
+	return node.Update(context.TODO(), lavalink.SessionUpdate{
+		Resuming: &resuming,
+		Timeout:  &timeout,
+	})
+}
+
+// Whether the player is playing the bot's queue, other guilds' players can't claim it when saving.
+func playingQueue(player disgolink.Player) bool {
+	track := player.Track()
+	return track != nil && track.Encoded == TRACKS.First().Encoded
+}
+
+// Rejoins the voice channel and continues playing the saved queue.
+// A player still alive in a resumed Lavalink session keeps its track and position.
+func restoreQueue() {
+	STATE.mu.Lock()
+	queue := STATE.Queue
+	STATE.mu.Unlock()
+
+	guildID := queue.GuildID
+
+	if len(queue.Tracks) == 0 || queue.VoiceChannelID == 0 {
+		return
+	}
+
+	guildLogger(guildID).Info("Restoring queue", "tracks", len(queue.Tracks))
+
+	encoded := make([]string, 0, len(queue.Tracks))
+	for _, saved := range queue.Tracks {
+		encoded = append(encoded, saved.Encoded)
+	}
+
+	decoded, err := loadingNode().DecodeTracks(context.TODO(), encoded)
+	if err != nil {
+		slog.Error("Error decoding saved tracks", "err", err)
+		return
+	}
+
+	for i, track := range decoded {
+		track, err = track.WithUserData(queue.Tracks[i].User)
+		if err != nil {
+			slog.Error("Error adding user data", "err", err)
+			continue
+		}
+
+		TRACKS.Push(track)
+	}
+
+	if TRACKS.Empty() {
+		return
+	}
+
+	GUILDS.Update(guildID, func(state *GuildState) {
+		state.TextChannelID = queue.TextChannelID
+	})
+
+	err = CLIENT.Bot.UpdateVoiceState(context.TODO(), guildID, &queue.VoiceChannelID, false, true)
+	if err != nil {
+		slog.Error("Error rejoining voice channel", "err", err)
+		return
+	}
+
+	player := CLIENT.Lavalink.Player(guildID)
+	if player.Track() != nil {
+		guildLogger(guildID).Info("Resumed Lavalink player")
+		return
+	}
+
+	volume := SETTINGS.Get(guildID).Volume
+	_, filters := guildFilters(guildID)
+
+	err = player.Update(context.TODO(),
+		lavalink.WithTrack(TRACKS.First()),
+		lavalink.WithPosition(queue.Position),
+		lavalink.WithPaused(queue.Paused),
+		lavalink.WithVolume(volume),
+		lavalink.WithFilters(filters),
+	)
+	if err != nil {
+		slog.Error("Error restoring player", "err", err)
+	}
+}
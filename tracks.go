@@ -2,10 +2,17 @@ package main
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	QUEUE_PAGE_SIZE = 10
 )
 
 type Tracks struct {
@@ -44,7 +51,7 @@ func (t *Tracks) Get(index int) lavalink.Track {
 	return track
 }
 
-func (t *Tracks) GetTrackEmbed(track lavalink.Track) discord.Embed {
+func (t *Tracks) GetTrackEmbed(guildID snowflake.ID, track lavalink.Track) discord.Embed {
 	info := track.Info
 	user := UserInfo{}
 
@@ -54,14 +61,25 @@ func (t *Tracks) GetTrackEmbed(track lavalink.Track) discord.Embed {
 	}
 
 	embed := discord.NewEmbedBuilder().
-		SetTitle(info.Title).
-		SetURL(*info.URI).
-		SetThumbnail(*info.ArtworkURL).
+		SetTitle(trackTitle(track)).
 		SetAuthor(info.Author, "", "").
-		SetFooter(fmt.Sprintf("Requested by %s", user.Username), user.Avatar).
-		Build()
+		SetFooter(fmt.Sprintf("Requested by %s", user.Username), user.Avatar)
+
+	// Files from the HTTP source have no artwork
+	if info.URI != nil {
+		embed.SetURL(*info.URI)
+	}
 
-	return embed
+	if info.ArtworkURL != nil {
+		embed.SetThumbnail(*info.ArtworkURL)
+	}
+
+	filter, _ := guildFilters(guildID)
+	if filter != FILTER_OFF {
+		embed.AddField("Filter", filter, true)
+	}
+
+	return embed.Build()
 }
 
 func (t *Tracks) Push(track lavalink.Track) {
@@ -78,12 +96,24 @@ func (t *Tracks) Pop() lavalink.Track {
 	return track
 }
 
+func (t *Tracks) Insert(index int, track lavalink.Track) {
+	t.mu.Lock()
+	t.store = slices.Insert(t.store, index, track)
+	t.mu.Unlock()
+}
+
 func (t *Tracks) Replace(index int, track lavalink.Track) {
 	t.mu.Lock()
 	t.store[index] = track
 	t.mu.Unlock()
 }
 
+func (t *Tracks) Clear() {
+	t.mu.Lock()
+	t.store = t.store[:0]
+	t.mu.Unlock()
+}
+
 func (t *Tracks) Len() int {
 	t.mu.Lock()
 	length := len(t.store)
@@ -97,3 +127,69 @@ func (t *Tracks) Empty() bool {
 	t.mu.Unlock()
 	return empty
 }
+
+func (t *Tracks) GetQueueEmbed(page int, position lavalink.Duration) (discord.Embed, int) {
+	t.mu.Lock()
+	tracks := make([]lavalink.Track, len(t.store))
+	copy(tracks, t.store)
+	t.mu.Unlock()
+
+	current := tracks[0]
+	upcoming := tracks[1:]
+
+	pages := (len(upcoming) + QUEUE_PAGE_SIZE - 1) / QUEUE_PAGE_SIZE
+	if pages == 0 {
+		pages = 1
+	}
+
+	page = max(0, min(page, pages-1))
+
+	// Streams have no length, the queue never ends while one is in it
+	remaining := lavalink.Duration(0)
+	streaming := false
+	for i, track := range tracks {
+		if track.Info.IsStream {
+			streaming = true
+			continue
+		}
+
+		remaining += track.Info.Length
+		if i == 0 {
+			remaining -= position
+		}
+	}
+
+	total := formatDuration(remaining)
+	if streaming {
+		total = "∞"
+	}
+
+	header := fmt.Sprintf("**Now playing:** %s `%s / %s`\n**Remaining:** `%s` | %d tracks in queue\n\n",
+		trackLink(current), formatDuration(position), formatTrackLength(current), total, len(upcoming))
+
+	start := page * QUEUE_PAGE_SIZE
+	end := min(start+QUEUE_PAGE_SIZE, len(upcoming))
+
+	entries := make([]string, 0, QUEUE_PAGE_SIZE)
+	for i, track := range upcoming[start:end] {
+		user := UserInfo{}
+		err := track.UserData.Unmarshal(&user)
+		if err != nil {
+			return discord.NewEmbedBuilder().SetDescription(err.Error()).Build(), pages
+		}
+
+		entries = append(entries, fmt.Sprintf("`%d.` %s - %s `%s` | %s", start+i+1, trackLink(track), track.Info.Author, formatTrackLength(track), user.Username))
+	}
+
+	if len(entries) == 0 {
+		entries = append(entries, "No upcoming tracks.")
+	}
+
+	embed := discord.NewEmbedBuilder().
+		SetTitle("Queue").
+		SetDescription(header+strings.Join(entries, "\n")).
+		SetFooter(fmt.Sprintf("Page %d/%d", page+1, pages), "").
+		Build()
+
+	return embed, pages
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
+	"strconv"
 	"strings"
 
 	"github.com/disgoorg/disgo/discord"
-	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
 	"github.com/disgoorg/disgolink/v3/disgolink"
 	"github.com/disgoorg/disgolink/v3/lavalink"
 	"github.com/disgoorg/snowflake/v2"
@@ -20,10 +22,37 @@ const (
 	ERR_EMOJI  = "❌"
 )
 
+// Implemented by both slash command and component interaction events.
+type Interaction interface {
+	discord.Interaction
+	CreateMessage(messageCreate discord.MessageCreate, opts ...rest.RequestOpt) error
+	DeferCreateMessage(ephemeral bool, opts ...rest.RequestOpt) error
+}
+
+// Wraps an interaction that was deferred, responses edit the deferred message instead of creating a new one.
+type DeferredInteraction struct {
+	Interaction
+}
+
+func (d DeferredInteraction) CreateMessage(messageCreate discord.MessageCreate, opts ...rest.RequestOpt) error {
+	update := discord.MessageUpdate{
+		Content:    &messageCreate.Content,
+		Embeds:     &messageCreate.Embeds,
+		Components: &messageCreate.Components,
+	}
+
+	_, err := CLIENT.Rest.UpdateInteractionResponse(d.ApplicationID(), d.Token(), update, opts...)
+	return err
+}
+
 type UserInfo struct {
-	Username string `json:"username"`
-	Avatar   string `json:"avatar"`
-	Skipped  bool   `json:"skipped"`
+	UserID    snowflake.ID `json:"user_id"`
+	Username  string       `json:"username"`
+	Avatar    string       `json:"avatar"`
+	ChannelID snowflake.ID `json:"channel_id"`
+	Retried   bool         `json:"retried"`
+	// Shown instead of the track's title, set for files without a title in their metadata
+	Title string `json:"title,omitempty"`
 }
 
 func isMentioned(mentions []discord.User) bool {
@@ -43,31 +72,68 @@ func isMentioned(mentions []discord.User) bool {
 func addReaction(channelID snowflake.ID, messageID snowflake.ID, emoji string) {
 	err := CLIENT.Rest.AddReaction(channelID, messageID, emoji)
 	if err != nil {
-		fmt.Printf("Error reacting: %v\n", err)
+		slog.Error("Error reacting", "err", err)
 	}
 }
 
-func reply(event *events.ApplicationCommandInteractionCreate, content string) {
+func reply(event Interaction, content string) {
 	message := discord.NewMessageCreateBuilder().SetContent(content).Build()
+	sendMessage(event, message)
+}
+
+func replyEphemeral(event Interaction, content string) {
+	message := discord.NewMessageCreateBuilder().SetContent(content).SetEphemeral(true).Build()
+	sendMessage(event, message)
+}
+
+func sendMessage(event Interaction, message discord.MessageCreate) {
+	// Responses to buttons are only shown to whoever pressed them
+	if event.Type() == discord.InteractionTypeComponent {
+		message.Flags = message.Flags.Add(discord.MessageFlagEphemeral)
+	}
 
 	err := event.CreateMessage(message)
 	if err != nil {
-		fmt.Printf("Error replying: %v\n", err)
+		eventLogger(event).Error("Error replying", "err", err)
 	}
 }
 
-func sendMessage(event *events.ApplicationCommandInteractionCreate, message discord.MessageCreate) {
-	err := event.CreateMessage(message)
+// Requester info attached to the tracks queued by the user.
+func newUserInfo(event Interaction) UserInfo {
+	avatar := ""
+	if event.User().AvatarURL() != nil {
+		avatar = *event.User().AvatarURL()
+	}
+
+	return UserInfo{
+		UserID:    event.User().ID,
+		Username:  event.User().EffectiveName(),
+		Avatar:    avatar,
+		ChannelID: event.Channel().ID(),
+	}
+}
+
+// Sends a message to the channel the track was requested from.
+func notifyTrackChannel(track lavalink.Track, content string) {
+	user := UserInfo{}
+	err := track.UserData.Unmarshal(&user)
+	if err != nil || user.ChannelID == 0 {
+		return
+	}
+
+	message := discord.NewMessageCreateBuilder().SetContent(content).Build()
+
+	_, err = CLIENT.Rest.CreateMessage(user.ChannelID, message)
 	if err != nil {
-		fmt.Printf("Error replying: %v\n", err)
+		slog.Error("Error notifying channel", "err", err)
 	}
 }
 
-func getBotVoiceState(event *events.ApplicationCommandInteractionCreate) *discord.VoiceState {
+func getBotVoiceState(event Interaction) *discord.VoiceState {
 	guildID := *event.GuildID()
 	voiceState, err := CLIENT.Rest.GetCurrentUserVoiceState(guildID)
 	if err != nil {
-		fmt.Printf("Error getting bot voice state: %v\n", err)
+		eventLogger(event).Error("Error getting bot voice state", "err", err)
 		reply(event, err.Error())
 		return nil
 	}
@@ -75,11 +141,11 @@ func getBotVoiceState(event *events.ApplicationCommandInteractionCreate) *discor
 	return voiceState
 }
 
-func updateVoiceChannel(event *events.ApplicationCommandInteractionCreate, channelID *snowflake.ID) {
+func updateVoiceChannel(event Interaction, channelID *snowflake.ID) {
 	guildID := *event.GuildID()
 	err := CLIENT.Bot.UpdateVoiceState(context.TODO(), guildID, channelID, false, true)
 	if err != nil {
-		fmt.Printf("Error updating voice channel: %v\n", err)
+		eventLogger(event).Error("Error updating voice channel", "err", err)
 		reply(event, err.Error())
 	}
 }
@@ -99,7 +165,7 @@ func readPDF(buffer bytes.Buffer) (string, error) {
 
 		text, err := page.GetPlainText(nil)
 		if err != nil {
-			fmt.Printf("Error extracting text from page %d: %v\n", i, err)
+			slog.Error("Error extracting text from page", "page", i, "err", err)
 			continue
 		}
 
@@ -113,13 +179,13 @@ func handleUserQuery(user UserInfo, query string) (lavalink.Track, error) {
 	var play lavalink.Track
 	var err error
 
-	CLIENT.Lavalink.BestNode().LoadTracksHandler(context.TODO(), query, disgolink.NewResultHandler(
+	loadingNode().LoadTracksHandler(context.TODO(), query, disgolink.NewResultHandler(
 		func(track lavalink.Track) {
-			fmt.Printf("Found track: %s\n", track.Info.Title)
+			slog.Debug("Found track", "track", track.Info.Title)
 			play = track
 		},
 		func(playlist lavalink.Playlist) {
-			fmt.Printf("Found playlist: %s\n", playlist.Info.Name)
+			slog.Debug("Found playlist", "playlist", playlist.Info.Name)
 			play = playlist.Tracks[0]
 		},
 		func(tracks []lavalink.Track) {
@@ -130,14 +196,14 @@ func handleUserQuery(user UserInfo, query string) (lavalink.Track, error) {
 			}
 
 			play = tracks[0]
-			fmt.Printf("Found %d tracks, choosing first one: %s\n", length, play.Info.Title)
+			slog.Debug("Found tracks, choosing the first one", "tracks", length, "track", play.Info.Title)
 		},
 		func() {
-			fmt.Println("No tracks found")
+			slog.Debug("No tracks found", "query", query)
 			err = ErrNoTracksFound
 		},
 		func(lavalinkErr error) {
-			fmt.Printf("Error loading tracks: %v\n", lavalinkErr)
+			slog.Error("Error loading tracks", "query", query, "err", lavalinkErr)
 			err = ErrLoadingTracks
 		},
 	))
@@ -145,10 +211,109 @@ func handleUserQuery(user UserInfo, query string) (lavalink.Track, error) {
 	if err == nil {
 		play, err = play.WithUserData(user)
 		if err != nil {
-			fmt.Printf("Error adding user data: %v\n", err)
+			slog.Error("Error adding user data", "err", err)
 			return play, err
 		}
 	}
 
 	return play, err
 }
+
+func formatDuration(duration lavalink.Duration) string {
+	if duration < 0 {
+		duration = 0
+	}
+
+	if duration.Hours() > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", duration.Hours(), duration.MinutesPart(), duration.SecondsPart())
+	}
+
+	return fmt.Sprintf("%d:%02d", duration.Minutes(), duration.SecondsPart())
+}
+
+func formatTrackLength(track lavalink.Track) string {
+	if track.Info.IsStream {
+		return "LIVE"
+	}
+
+	return formatDuration(track.Info.Length)
+}
+
+// The title set by the requester's user data, or the one Lavalink found.
+func trackTitle(track lavalink.Track) string {
+	user := UserInfo{}
+	err := track.UserData.Unmarshal(&user)
+	if err == nil && user.Title != "" {
+		return user.Title
+	}
+
+	return track.Info.Title
+}
+
+func trackLink(track lavalink.Track) string {
+	if track.Info.URI == nil {
+		return trackTitle(track)
+	}
+
+	return fmt.Sprintf("[%s](%s)", trackTitle(track), *track.Info.URI)
+}
+
+// Parses "1:23", "83" or "30s" as an absolute position, or "+30s"/"-10s" relative to the current one.
+func parseSeekPosition(input string, current lavalink.Duration) (lavalink.Duration, error) {
+	input = strings.TrimSpace(input)
+
+	sign := 0
+	if strings.HasPrefix(input, "+") {
+		sign = 1
+	} else if strings.HasPrefix(input, "-") {
+		sign = -1
+	}
+
+	if sign != 0 {
+		input = input[1:]
+	}
+
+	input = strings.TrimSuffix(input, "s")
+
+	parts := strings.Split(input, ":")
+	if len(parts) > 3 {
+		return 0, ErrInvalidPosition
+	}
+
+	var position lavalink.Duration
+	for _, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return 0, ErrInvalidPosition
+		}
+
+		position = position*60 + lavalink.Duration(value)
+	}
+
+	position *= lavalink.Second
+
+	if sign != 0 {
+		position = max(0, current+lavalink.Duration(sign)*position)
+	}
+
+	return position, nil
+}
+
+func progressBar(position lavalink.Duration, track lavalink.Track) string {
+	if track.Info.IsStream {
+		return "🔴 LIVE"
+	}
+
+	const size = 20
+
+	filled := 0
+	if track.Info.Length > 0 {
+		filled = int(position * size / track.Info.Length)
+	}
+
+	filled = max(0, min(filled, size-1))
+
+	bar := strings.Repeat("▬", filled) + "🔘" + strings.Repeat("▬", size-filled-1)
+
+	return fmt.Sprintf("%s `%s / %s`", bar, formatDuration(position), formatDuration(track.Info.Length))
+}
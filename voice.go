@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgolink/v3/lavalink"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// Returns the voice channel the bot is connected to according to the gateway cache.
+func botVoiceChannel(guildID snowflake.ID) *snowflake.ID {
+	voice, ok := CLIENT.Bot.Caches().VoiceState(guildID, CLIENT.Bot.ID())
+	if !ok {
+		return nil
+	}
+
+	return voice.ChannelID
+}
+
+// Returns the users listening in the bot's voice channel, excluding bots.
+func voiceListeners(guildID snowflake.ID) []snowflake.ID {
+	channelID := botVoiceChannel(guildID)
+	if channelID == nil {
+		return nil
+	}
+
+	listeners := []snowflake.ID{}
+
+	CLIENT.Bot.Caches().VoiceStatesForEach(guildID, func(voice discord.VoiceState) {
+		if voice.ChannelID == nil || *voice.ChannelID != *channelID {
+			return
+		}
+
+		member, ok := CLIENT.Bot.Caches().Member(guildID, voice.UserID)
+		if ok && member.User.Bot {
+			return
+		}
+
+		listeners = append(listeners, voice.UserID)
+	})
+
+	return listeners
+}
+
+// Starts or stops the idle timer depending on whether the bot is alone or has nothing to play.
+// Being left alone pauses the player right away, it is resumed if someone joins before the timer fires.
+func checkIdle(guildID snowflake.ID) {
+	// Called on every voice state update, most guilds don't have a player
+	if CLIENT.Lavalink == nil || CLIENT.Lavalink.ExistingPlayer(guildID) == nil {
+		stopIdleTimer(guildID)
+		return
+	}
+
+	if botVoiceChannel(guildID) == nil || SETTINGS.Get(guildID).AlwaysOn {
+		stopIdleTimer(guildID)
+		return
+	}
+
+	alone := len(voiceListeners(guildID)) == 0
+	idle := TRACKS.Empty()
+
+	if !alone && !idle {
+		stopIdleTimer(guildID)
+		return
+	}
+
+	if alone && !idle {
+		player := CLIENT.Lavalink.Player(guildID)
+
+		if !player.Paused() {
+			err := player.Update(context.TODO(), lavalink.WithPaused(true))
+			if err != nil {
+				guildLogger(guildID).Error("Error pausing idle player", "err", err)
+			} else {
+				GUILDS.Update(guildID, func(state *GuildState) {
+					state.IdlePaused = true
+				})
+			}
+		}
+	}
+
+	GUILDS.Update(guildID, func(state *GuildState) {
+		if state.IdleTimer != nil {
+			return
+		}
+
+		guildLogger(guildID).Info("Starting idle timer")
+		state.IdleTimer = time.AfterFunc(time.Duration(CONFIG.Load().Music.IdleTimeout)*time.Minute, func() {
+			leaveIdle(guildID)
+		})
+	})
+}
+
+func stopIdleTimer(guildID snowflake.ID) {
+	state := GUILDS.Get(guildID)
+	if state.IdleTimer == nil && !state.IdlePaused {
+		return
+	}
+
+	if state.IdleTimer != nil {
+		state.IdleTimer.Stop()
+	}
+
+	GUILDS.Update(guildID, func(state *GuildState) {
+		state.IdleTimer = nil
+		state.IdlePaused = false
+	})
+
+	if state.IdlePaused && !TRACKS.Empty() {
+		err := CLIENT.Lavalink.Player(guildID).Update(context.TODO(), lavalink.WithPaused(false))
+		if err != nil {
+			guildLogger(guildID).Error("Error resuming idle player", "err", err)
+		}
+	}
+}
+
+func leaveIdle(guildID snowflake.ID) {
+	GUILDS.Update(guildID, func(state *GuildState) {
+		state.IdleTimer = nil
+		state.IdlePaused = false
+	})
+
+	if botVoiceChannel(guildID) == nil || SETTINGS.Get(guildID).AlwaysOn {
+		return
+	}
+
+	alone := len(voiceListeners(guildID)) == 0
+	if !alone && !TRACKS.Empty() {
+		return
+	}
+
+	guildLogger(guildID).Info("Leaving voice channel after being idle")
+
+	// Clearing first so onTrackEnd doesn't start the next track
+	TRACKS.Clear()
+
+	err := CLIENT.Lavalink.Player(guildID).Update(context.TODO(), lavalink.WithNullTrack())
+	if err != nil {
+		guildLogger(guildID).Error("Error stopping idle player", "err", err)
+	}
+
+	err = CLIENT.Bot.UpdateVoiceState(context.TODO(), guildID, nil, false, true)
+	if err != nil {
+		guildLogger(guildID).Error("Error leaving voice channel", "err", err)
+		return
+	}
+
+	channelID := GUILDS.Get(guildID).TextChannelID
+	if channelID == 0 {
+		return
+	}
+
+	reason := "nothing was playing"
+	if alone {
+		reason = "everyone left"
+	}
+
+	message := discord.NewMessageCreateBuilder().
+		SetContent(fmt.Sprintf("Left the voice channel because %s for %d minutes.", reason, CONFIG.Load().Music.IdleTimeout)).
+		Build()
+
+	_, err = CLIENT.Rest.CreateMessage(channelID, message)
+	if err != nil {
+		guildLogger(guildID).Error("Error sending idle message", "err", err)
+	}
+}
+
+func alwaysOn(event *events.ApplicationCommandInteractionCreate) {
+	guildID := *event.GuildID()
+
+	enabled := !SETTINGS.Get(guildID).AlwaysOn
+
+	err := SETTINGS.Update(guildID, func(settings *GuildSettings) {
+		settings.AlwaysOn = enabled
+	})
+	if err != nil {
+		eventLogger(event).Error("Error saving settings", "err", err)
+		reply(event, err.Error())
+		return
+	}
+
+	checkIdle(guildID)
+
+	if enabled {
+		reply(event, "24/7 mode enabled, the bot will stay in the voice channel.")
+		return
+	}
+
+	reply(event, fmt.Sprintf("24/7 mode disabled, the bot will leave after %d minutes of inactivity.", CONFIG.Load().Music.IdleTimeout))
+}